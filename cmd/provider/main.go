@@ -3,14 +3,25 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // serve gzip-compressed responses to clients that request them
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
@@ -19,27 +30,160 @@ import (
 
 var version = "dev"
 
+// defaultAnnounceKey is the key of the port announcement expected by the Nomos CLI
+const defaultAnnounceKey = "PROVIDER_PORT"
+
+// announceKey returns the port announcement key, overridable via NOMOS_PORT_ANNOUNCE_KEY
+func announceKey() string {
+	if key := os.Getenv("NOMOS_PORT_ANNOUNCE_KEY"); key != "" {
+		return key
+	}
+	return defaultAnnounceKey
+}
+
+// defaultBindAddr binds to a random loopback port
+const defaultBindAddr = "127.0.0.1:0"
+
+// bindAddr returns the listen address, overridable via NOMOS_BIND_ADDR
+func bindAddr() string {
+	if addr := os.Getenv("NOMOS_BIND_ADDR"); addr != "" {
+		return addr
+	}
+	return defaultBindAddr
+}
+
+// listen validates addr as host:port and opens a TCP listener on it
+func listen(addr string) (net.Listener, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("invalid bind address %q: %w", addr, err)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind %s: %w", addr, err)
+	}
+	return listener, nil
+}
+
+// metricsAddr returns the metrics listen address from NOMOS_METRICS_ADDR, or
+// "" when metrics are disabled. An address without a host binds to loopback.
+func metricsAddr() string {
+	addr := os.Getenv("NOMOS_METRICS_ADDR")
+	if strings.HasPrefix(addr, ":") {
+		return "127.0.0.1" + addr
+	}
+	return addr
+}
+
+// serveMetrics starts an HTTP server exposing provider metrics at /metrics
+func serveMetrics(addr string, prov *provider.Provider, log *logger.Logger) (*http.Server, error) {
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prov.MetricsHandler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics server error: %v", err)
+		}
+	}()
+	log.Info("serving metrics on: http://%s/metrics", listener.Addr())
+	return srv, nil
+}
+
+// announcePort writes the KEY=PORT announcement line to w
+func announcePort(w io.Writer, key string, port int) error {
+	_, err := fmt.Fprintf(w, "%s=%d\n", key, port)
+	return err
+}
+
+// cliOptions holds the command-line options
+type cliOptions struct {
+	check      bool
+	configPath string
+}
+
+// parseFlags parses the command-line arguments of the program called name.
+// Unknown flags, malformed flag values, and positional arguments are logged
+// and skipped rather than aborting, so a launcher passing extra arguments
+// cannot stop the provider from starting. Returns flag.ErrHelp, after
+// printing the usage to stderr, when help was requested.
+func parseFlags(name string, args []string, log *logger.Logger) (cliOptions, error) {
+	var opts cliOptions
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&opts.check, "check", envBool("NOMOS_SELF_CHECK"), "validate configuration and required variables, then exit")
+	fs.StringVar(&opts.configPath, "config", os.Getenv("NOMOS_CHECK_CONFIG"), "path to a JSON config file used by --check")
+
+	// Parsing stops at the first bad or positional argument; skip it and resume
+	for len(args) > 0 {
+		err := fs.Parse(args)
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			fs.SetOutput(os.Stderr)
+			fs.Usage()
+			return opts, err
+		case err != nil:
+			log.Warn("ignoring command-line argument: %v", err)
+			args = fs.Args()
+		case fs.NArg() > 0:
+			log.Warn("ignoring command-line argument: %s", fs.Arg(0))
+			args = fs.Args()[1:]
+		default:
+			args = nil
+		}
+	}
+	return opts, nil
+}
+
 func main() {
 	// Create logger (writes to stderr)
 	log := logger.New(logger.INFO)
 
+	opts, err := parseFlags(os.Args[0], os.Args[1:], log)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	// Self-check mode: run Init once and report instead of serving
+	if opts.check {
+		provider.Version = version
+		os.Exit(runCheck(opts.configPath, os.Stdout, log))
+	}
+
 	// Create provider instance
 	prov := provider.New(log)
 
 	// Set version from build
 	provider.Version = version
 
+	// Optional per-RPC tracing, exported over OTLP when an endpoint is configured
+	var interceptors []grpc.UnaryServerInterceptor
+	tracing, flushTraces, err := setupTracing(context.Background(), log)
+	if err != nil {
+		log.Error("failed to set up tracing: %v", err)
+		os.Exit(1)
+	}
+	if tracing != nil {
+		interceptors = append(interceptors, tracing)
+	}
+
+	// Optional per-RPC access logging
+	if envBool("NOMOS_LOG_REQUESTS") {
+		interceptors = append(interceptors, provider.LoggingInterceptor(log))
+	}
+
 	// Create gRPC server
-	grpcServer := grpc.NewServer(
-		grpc.MaxRecvMsgSize(10*1024*1024), // 10MB max message size
-		grpc.MaxSendMsgSize(10*1024*1024),
-	)
+	grpcServer := newServer(maxMessageBytes(log), interceptors)
 
-	// Register provider service
-	pb.RegisterProviderServiceServer(grpcServer, prov)
+	// Register provider service (and reflection when enabled for debugging)
+	registerServices(grpcServer, prov, envBool("NOMOS_ENABLE_REFLECTION"))
 
-	// Listen on random port (loopback only)
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	// Listen on random port (loopback only unless overridden)
+	listener, err := listen(bindAddr())
 	if err != nil {
 		log.Error("failed to listen: %v", err)
 		os.Exit(1)
@@ -47,8 +191,21 @@ func main() {
 
 	port := listener.Addr().(*net.TCPAddr).Port
 
+	// Optional Prometheus metrics endpoint
+	var metricsServer *http.Server
+	if addr := metricsAddr(); addr != "" {
+		metricsServer, err = serveMetrics(addr, prov, log)
+		if err != nil {
+			log.Error("failed to start metrics server: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	// Print PORT announcement to stdout (required by CLI)
-	fmt.Printf("PROVIDER_PORT=%d\n", port)
+	if err := announcePort(os.Stdout, announceKey(), port); err != nil {
+		log.Error("failed to announce port: %v", err)
+		os.Exit(1)
+	}
 	if err := os.Stdout.Sync(); err != nil {
 		log.Error("failed to flush stdout: %v", err)
 	}
@@ -56,7 +213,7 @@ func main() {
 	// Log startup to stderr
 	log.Info("environment-variables provider starting")
 	log.Info("version: %s", version)
-	log.Info("listening on: 127.0.0.1:%d", port)
+	log.Info("listening on: %s", listener.Addr())
 
 	// Setup signal handling for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -70,10 +227,21 @@ func main() {
 		}
 	}()
 
-	// Wait for shutdown signal or server error
+	// Optionally shut down after a period without RPCs
+	idle := idleTimeout(log)
+	var idleCh <-chan struct{}
+	if idle > 0 {
+		stopIdle := make(chan struct{})
+		defer close(stopIdle)
+		idleCh = watchIdle(prov, idle, stopIdle)
+	}
+
+	// Wait for shutdown signal, idle timeout, or server error
 	select {
 	case sig := <-sigCh:
 		log.Info("received signal: %v", sig)
+	case <-idleCh:
+		log.Info("no requests for %s, shutting down", idle)
 	case err := <-errCh:
 		log.Error("server error: %v", err)
 		os.Exit(1)
@@ -81,15 +249,190 @@ func main() {
 
 	// Graceful shutdown
 	log.Info("shutting down gracefully")
+	shutdown(prov, grpcServer, shutdownTimeout(log), log)
+	if metricsServer != nil {
+		_ = metricsServer.Close()
+	}
+	if flushTraces != nil {
+		flushTraces()
+	}
+	log.Info("shutdown complete")
+}
 
-	// Call provider shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// defaultMaxMessageBytes is the default maximum gRPC message size (10MB)
+const defaultMaxMessageBytes = 10 * 1024 * 1024
+
+// maxMessageBytes returns the maximum gRPC message size in bytes from
+// NOMOS_MAX_MESSAGE_BYTES, falling back to the default when unset or invalid.
+func maxMessageBytes(log *logger.Logger) int {
+	raw := os.Getenv("NOMOS_MAX_MESSAGE_BYTES")
+	if raw == "" {
+		return defaultMaxMessageBytes
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		log.Warn("invalid NOMOS_MAX_MESSAGE_BYTES %q, using %d", raw, defaultMaxMessageBytes)
+		return defaultMaxMessageBytes
+	}
+	return limit
+}
+
+// newServer creates the gRPC server with maxMsgBytes as both the receive and
+// send message size limit, chaining interceptors in order
+func newServer(maxMsgBytes int, interceptors []grpc.UnaryServerInterceptor) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxMsgBytes),
+		grpc.MaxSendMsgSize(maxMsgBytes),
+	}
+	if len(interceptors) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(interceptors...))
+	}
+	return grpc.NewServer(opts...)
+}
+
+// registerServices registers the provider service on srv. With withReflection,
+// the gRPC server reflection service is registered too so that tools such as
+// grpcurl can discover the API without the proto files.
+func registerServices(srv *grpc.Server, prov *provider.Provider, withReflection bool) {
+	pb.RegisterProviderServiceServer(srv, prov)
+	if withReflection {
+		reflection.Register(srv)
+	}
+}
+
+// loadCheckConfig reads a JSON object from path. An empty path yields an
+// empty config so that defaults are validated.
+func loadCheckConfig(path string) (*structpb.Struct, error) {
+	if path == "" {
+		return &structpb.Struct{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	cfg, err := structpb.NewStruct(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// runCheck initializes a provider with the config at configPath, writes the
+// result to out and returns the process exit code
+func runCheck(configPath string, out io.Writer, log *logger.Logger) int {
+	cfg, err := loadCheckConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(out, "FAILED: %v\n", err)
+		return 1
+	}
+
+	prov := provider.New(log)
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "self-check", Config: cfg}); err != nil {
+		fmt.Fprintf(out, "FAILED: %s\n", status.Convert(err).Message())
+		return 1
+	}
+
+	fmt.Fprintln(out, "OK: configuration is valid")
+	return 0
+}
+
+// stopper is the subset of *grpc.Server used during shutdown
+type stopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// defaultShutdownTimeout bounds the graceful shutdown sequence
+const defaultShutdownTimeout = 5 * time.Second
+
+// shutdownTimeout returns the shutdown timeout, overridable via NOMOS_SHUTDOWN_TIMEOUT
+// (a Go duration such as "10s"). Invalid values fall back to the default.
+func shutdownTimeout(log *logger.Logger) time.Duration {
+	raw := os.Getenv("NOMOS_SHUTDOWN_TIMEOUT")
+	if raw == "" {
+		return defaultShutdownTimeout
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		log.Warn("invalid NOMOS_SHUTDOWN_TIMEOUT %q, using %s", raw, defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+// idleTimeout returns the idle shutdown window from NOMOS_IDLE_TIMEOUT (a Go
+// duration such as "5m"), or zero when unset or invalid, disabling it.
+func idleTimeout(log *logger.Logger) time.Duration {
+	raw := os.Getenv("NOMOS_IDLE_TIMEOUT")
+	if raw == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		log.Warn("invalid NOMOS_IDLE_TIMEOUT %q, idle shutdown disabled", raw)
+		return 0
+	}
+	return timeout
+}
+
+// watchIdle returns a channel that is closed once prov has handled no RPC
+// for timeout. The watcher exits early when stop is closed.
+func watchIdle(prov *provider.Provider, timeout time.Duration, stop <-chan struct{}) <-chan struct{} {
+	idle := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-timer.C:
+				remaining := timeout - time.Since(prov.LastActivity())
+				if remaining <= 0 {
+					close(idle)
+					return
+				}
+				timer.Reset(remaining)
+			}
+		}
+	}()
+	return idle
+}
+
+// shutdown shuts down the provider and gracefully stops the server. If the
+// sequence exceeds timeout, the server is stopped forcefully so the process
+// always exits.
+func shutdown(prov *provider.Provider, srv stopper, timeout time.Duration, log *logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+
+	// Call provider shutdown
 	if _, err := prov.Shutdown(ctx, &pb.ShutdownRequest{}); err != nil {
 		log.Error("error during shutdown: %v", err)
 	}
 
-	// Stop gRPC server
-	grpcServer.GracefulStop()
-	log.Info("shutdown complete")
+	// Stop gRPC server, forcing the stop if draining takes too long
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warn("graceful stop exceeded %s, forcing stop", timeout)
+		srv.Stop()
+		<-done
+	}
+}
+
+// envBool reports whether the named environment variable is set to a true value
+func envBool(name string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && enabled
 }
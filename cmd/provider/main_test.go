@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+func TestAnnouncePort(t *testing.T) {
+	tests := []struct {
+		name   string
+		envKey string
+		want   string
+	}{
+		{"default key", "", "PROVIDER_PORT=4242\n"},
+		{"custom key", "HARNESS_PORT", "HARNESS_PORT=4242\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NOMOS_PORT_ANNOUNCE_KEY", tt.envKey)
+
+			var buf bytes.Buffer
+			if err := announcePort(&buf, announceKey(), 4242); err != nil {
+				t.Fatalf("announcePort() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListen(t *testing.T) {
+	// Reserve a free loopback port, then release it for the listener under test
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	wantPort := probe.Addr().(*net.TCPAddr).Port
+	if err := probe.Close(); err != nil {
+		t.Fatalf("failed to release port: %v", err)
+	}
+
+	t.Setenv("NOMOS_BIND_ADDR", fmt.Sprintf("127.0.0.1:%d", wantPort))
+
+	listener, err := listen(bindAddr())
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	var buf bytes.Buffer
+	if err := announcePort(&buf, defaultAnnounceKey, listener.Addr().(*net.TCPAddr).Port); err != nil {
+		t.Fatalf("announcePort() error = %v", err)
+	}
+	if want := fmt.Sprintf("PROVIDER_PORT=%d\n", wantPort); buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestListenInvalidAddress(t *testing.T) {
+	tests := []string{"not-an-address", "127.0.0.1", "127.0.0.1:notaport"}
+
+	for _, addr := range tests {
+		t.Run(addr, func(t *testing.T) {
+			listener, err := listen(addr)
+			if err == nil {
+				listener.Close()
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), addr) {
+				t.Errorf("expected error to name the address, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBindAddrDefault(t *testing.T) {
+	t.Setenv("NOMOS_BIND_ADDR", "")
+	if got := bindAddr(); got != defaultBindAddr {
+		t.Errorf("got %q, want %q", got, defaultBindAddr)
+	}
+}
+
+// slowStopper simulates a server whose GracefulStop hangs until Stop is called
+type slowStopper struct {
+	stopped chan struct{}
+	forced  bool
+}
+
+func (s *slowStopper) GracefulStop() {
+	<-s.stopped
+}
+
+func (s *slowStopper) Stop() {
+	s.forced = true
+	close(s.stopped)
+}
+
+func TestShutdownForcesStopAfterTimeout(t *testing.T) {
+	log := logger.NewWithOutput(logger.ERROR, io.Discard)
+	srv := &slowStopper{stopped: make(chan struct{})}
+
+	start := time.Now()
+	shutdown(provider.New(log), srv, 50*time.Millisecond, log)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %s, expected it to be bounded by the timeout", elapsed)
+	}
+	if !srv.forced {
+		t.Error("expected Stop to be called after GracefulStop exceeded the timeout")
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	log := logger.NewWithOutput(logger.ERROR, io.Discard)
+
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{"default", "", defaultShutdownTimeout},
+		{"custom", "250ms", 250 * time.Millisecond},
+		{"invalid falls back", "soon", defaultShutdownTimeout},
+		{"negative falls back", "-1s", defaultShutdownTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NOMOS_SHUTDOWN_TIMEOUT", tt.env)
+			if got := shutdownTimeout(log); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Setenv("NOMOS_CHECK_TEST_PRESENT", "value")
+
+	tests := []struct {
+		name     string
+		config   string
+		wantCode int
+		wantOut  string
+	}{
+		{"defaults", "", 0, "OK"},
+		{"valid config", `{"separator": "_", "required_variables": ["NOMOS_CHECK_TEST_PRESENT"]}`, 0, "OK"},
+		{"invalid option", `{"case_transform": "shouting"}`, 1, "FAILED"},
+		{"missing required", `{"required_variables": ["NOMOS_CHECK_TEST_ABSENT"]}`, 1, "NOMOS_CHECK_TEST_ABSENT"},
+		{"malformed json", `{"separator":`, 1, "failed to parse config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.config != "" {
+				path = filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+					t.Fatalf("failed to write config: %v", err)
+				}
+			}
+
+			var out bytes.Buffer
+			code := runCheck(path, &out, logger.NewWithOutput(logger.ERROR, io.Discard))
+			if code != tt.wantCode {
+				t.Errorf("runCheck() = %d, want %d (output %q)", code, tt.wantCode, out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("expected output to contain %q, got %q", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("NOMOS_SELF_CHECK", "")
+	t.Setenv("NOMOS_CHECK_CONFIG", "")
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       cliOptions
+		wantLogged []string
+	}{
+		{"no arguments", nil, cliOptions{}, nil},
+		{"known flags", []string{"--check", "--config", "c.json"}, cliOptions{check: true, configPath: "c.json"}, nil},
+		{"unknown flag", []string{"--port=4242", "--check"}, cliOptions{check: true}, []string{"-port"}},
+		{"positional argument", []string{"serve", "--config=c.json"}, cliOptions{configPath: "c.json"}, []string{"serve"}},
+		{"malformed value", []string{"--check=maybe", "--config", "c.json"}, cliOptions{configPath: "c.json"}, []string{"-check"}},
+		{"several unknown", []string{"--a", "x", "--b"}, cliOptions{}, []string{"-a", "x", "-b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			got, err := parseFlags("provider", tt.args, logger.NewWithOutput(logger.WARN, &buf))
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseFlags() = %+v, want %+v", got, tt.want)
+			}
+			logged := buf.String()
+			if got := strings.Count(logged, "ignoring command-line argument"); got != len(tt.wantLogged) {
+				t.Errorf("expected %d ignored arguments logged, got %d: %q", len(tt.wantLogged), got, logged)
+			}
+			for _, arg := range tt.wantLogged {
+				if !strings.Contains(logged, arg) {
+					t.Errorf("expected log to name %q, got %q", arg, logged)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricsAddr(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{"", ""},
+		{":9090", "127.0.0.1:9090"},
+		{"0.0.0.0:9090", "0.0.0.0:9090"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("NOMOS_METRICS_ADDR", tt.env)
+			if got := metricsAddr(); got != tt.want {
+				t.Errorf("metricsAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterServicesReflection(t *testing.T) {
+	tests := []struct {
+		name           string
+		withReflection bool
+	}{
+		{"reflection enabled", true},
+		{"reflection disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := grpc.NewServer()
+			registerServices(srv, provider.New(logger.NewWithOutput(logger.ERROR, io.Discard)), tt.withReflection)
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("listen: %v", err)
+			}
+			go func() { _ = srv.Serve(lis) }()
+			defer srv.Stop()
+
+			conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatalf("dial: %v", err)
+			}
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			if err != nil {
+				t.Fatalf("open reflection stream: %v", err)
+			}
+			if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			resp, err := stream.Recv()
+
+			if !tt.withReflection {
+				if status.Code(err) != codes.Unimplemented {
+					t.Errorf("expected Unimplemented without reflection, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("recv: %v", err)
+			}
+			var found bool
+			for _, svc := range resp.GetListServicesResponse().GetService() {
+				if svc.GetName() == pb.ProviderService_ServiceDesc.ServiceName {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("provider service not listed by reflection: %v", resp.GetListServicesResponse().GetService())
+			}
+		})
+	}
+}
+
+func TestGzipCompressedFetch(t *testing.T) {
+	value := strings.Repeat("compressible-", 10000)
+	t.Setenv("GZIP_TEST_LARGE_VALUE", value)
+
+	srv := grpc.NewServer()
+	registerServices(srv, provider.New(logger.NewWithOutput(logger.ERROR, io.Discard)), false)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := pb.NewProviderServiceClient(conn)
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "gzip-test"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"GZIP_TEST_LARGE_VALUE"}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != value {
+		t.Errorf("decoded value differs: got %d bytes, want %d", len(got), len(value))
+	}
+}
+
+func TestMaxMessageBytes(t *testing.T) {
+	log := logger.NewWithOutput(logger.ERROR, io.Discard)
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", defaultMaxMessageBytes},
+		{"1048576", 1048576},
+		{"invalid", defaultMaxMessageBytes},
+		{"0", defaultMaxMessageBytes},
+		{"-1", defaultMaxMessageBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("NOMOS_MAX_MESSAGE_BYTES", tt.value)
+			if got := maxMessageBytes(log); got != tt.want {
+				t.Errorf("maxMessageBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewServerMessageLimit(t *testing.T) {
+	const limit = 4096
+	t.Setenv("MESSAGE_LIMIT_TEST_SMALL", "small")
+	t.Setenv("MESSAGE_LIMIT_TEST_LARGE", strings.Repeat("x", 2*limit))
+
+	srv := newServer(limit, nil)
+	registerServices(srv, provider.New(logger.NewWithOutput(logger.ERROR, io.Discard)), false)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := pb.NewProviderServiceClient(conn)
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "message-limit-test"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"MESSAGE_LIMIT_TEST_SMALL"}}); err != nil {
+		t.Fatalf("fetch within limit failed: %v", err)
+	}
+
+	// Response larger than the send limit
+	_, err = client.Fetch(ctx, &pb.FetchRequest{Path: []string{"MESSAGE_LIMIT_TEST_LARGE"}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted for oversized response, got %v", err)
+	}
+
+	// Request larger than the receive limit
+	_, err = client.Fetch(ctx, &pb.FetchRequest{Path: []string{strings.Repeat("A", 2*limit)}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted for oversized request, got %v", err)
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	log := logger.NewWithOutput(logger.ERROR, io.Discard)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"250ms", 250 * time.Millisecond},
+		{"invalid", 0},
+		{"-1s", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("NOMOS_IDLE_TIMEOUT", tt.value)
+			if got := idleTimeout(log); got != tt.want {
+				t.Errorf("idleTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchIdle(t *testing.T) {
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	stop := make(chan struct{})
+	defer close(stop)
+
+	const timeout = 100 * time.Millisecond
+	start := time.Now()
+	idle := watchIdle(prov, timeout, stop)
+
+	// Activity before the window elapses postpones the shutdown
+	time.Sleep(60 * time.Millisecond)
+	if _, err := prov.Health(context.Background(), &pb.HealthRequest{}); err != nil {
+		t.Fatalf("health failed: %v", err)
+	}
+
+	select {
+	case <-idle:
+		if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+			t.Errorf("idle fired after %v, expected activity to postpone it", elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle shutdown did not fire")
+	}
+}
@@ -0,0 +1,59 @@
+//go:build otel
+// +build otel
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+)
+
+// tracerName identifies the instrumentation scope of provider spans
+const tracerName = "github.com/autonomous-bits/nomos-provider-environment-variables"
+
+// setupTracing returns the tracing interceptor and a function flushing
+// pending spans, or nils when OTEL_EXPORTER_OTLP_ENDPOINT is unset. Incoming
+// W3C trace context and baggage are propagated into the provider spans.
+func setupTracing(ctx context.Context, log *logger.Logger) (grpc.UnaryServerInterceptor, func(), error) {
+	tp, err := tracerProvider(ctx)
+	if err != nil || tp == nil {
+		return nil, nil, err
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.Info("exporting traces to: %s", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	return provider.TracingInterceptor(tp.Tracer(tracerName)), func() { flushTraces(tp, log) }, nil
+}
+
+// tracerProvider returns a tracer provider exporting spans over OTLP/gRPC when
+// OTEL_EXPORTER_OTLP_ENDPOINT is set, or nil when tracing is disabled. The
+// exporter reads the remaining standard OTEL_EXPORTER_OTLP_* variables itself.
+func tracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return nil, nil
+	}
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter)), nil
+}
+
+// flushTraces exports pending spans and stops the tracer provider
+func flushTraces(tp *sdktrace.TracerProvider, log *logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		log.Error("failed to flush traces: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !otel
+// +build !otel
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"google.golang.org/grpc"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+)
+
+// setupTracing is a no-op in builds without the otel build tag. A configured
+// OTEL_EXPORTER_OTLP_ENDPOINT is reported as ignored.
+func setupTracing(_ context.Context, log *logger.Logger) (grpc.UnaryServerInterceptor, func(), error) {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		log.Warn("OTEL_EXPORTER_OTLP_ENDPOINT is set but tracing is not built in (build with -tags otel), ignoring %s", endpoint)
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,30 @@
+//go:build otel
+// +build otel
+
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+)
+
+func TestTracerProviderOptIn(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	tp, err := tracerProvider(context.Background())
+	if err != nil || tp != nil {
+		t.Fatalf("expected tracing disabled without an endpoint, got %v, %v", tp, err)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:4317")
+	tp, err = tracerProvider(context.Background())
+	if err != nil {
+		t.Fatalf("tracerProvider failed: %v", err)
+	}
+	if tp == nil {
+		t.Fatal("expected a tracer provider when an endpoint is set")
+	}
+	flushTraces(tp, logger.NewWithOutput(logger.ERROR, io.Discard))
+}
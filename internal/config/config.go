@@ -4,31 +4,192 @@ package config
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
 )
 
+// DefaultCaseTransform is the case_transform used when the config sets none.
+// Distributions can change it at build time with
+// -ldflags "-X github.com/autonomous-bits/nomos-provider-environment-variables/internal/config.DefaultCaseTransform=lower".
+var DefaultCaseTransform = "upper"
+
+// MaxRequiredVariables bounds the size of the required_variables list
+const MaxRequiredVariables = 1000
+
 // Config represents the provider configuration
 type Config struct {
-	Separator            string
-	CaseTransform        string
-	Prefix               string
-	PrefixMode           string
-	RequiredVariables    []string
-	EnableTypeConversion bool
-	EnableJSONParsing    bool
+	Separator                     string
+	CaseTransform                 string
+	Prefix                        string
+	PrefixMode                    string
+	RequiredVariables             []string
+	EnableTypeConversion          bool
+	EnableJSONParsing             bool
+	NullAsNull                    bool
+	EnableSizeParsing             bool
+	JSONSchemas                   map[string]*converter.Schema
+	StripQuotes                   bool
+	ExpandReferences              bool
+	StrictExpansion               bool
+	MaxConcurrentFetches          int
+	FailOnLimit                   bool
+	IncludeType                   bool
+	GroupIndexed                  bool
+	PrefixSeparator               string
+	SegmentTransforms             []string
+	IncludeResolvedName           bool
+	CustomConverters              []string
+	PassthroughUnfiltered         bool
+	ConversionCacheSize           int
+	ConditionalRequirements       []ConditionalRequirement
+	BinaryEncoding                string
+	JSONNumbersAsStrings          bool
+	ScreamingChars                string
+	RequestTimeoutMS              int
+	NewlineAsArray                bool
+	TrimWhitespace                bool
+	BooleanOutput                 string
+	DeprecatedVariables           map[string]string
+	IncludePresent                bool
+	EnableRelaxedJSON             bool
+	TypeNameMap                   map[string]string
+	PrefixConversionOverrides     map[string]ConversionOverride
+	CollapseSeparators            bool
+	StrictConversion              bool
+	QuotedAsString                bool
+	VariableMaxSizes              map[string]int
+	RequiredMissingAsPrecondition bool
+	DeclaredPaths                 [][]string
+	DeclaredPathsRequired         bool
+	AutoPrefixSeparator           bool
+	EnableDurationParsing         bool
+	CamelSplit                    bool
+	EmitConversionWarnings        bool
+	LogFetchSuccess               bool
+	NameCharMap                   map[string]string
+	MaxArrayLength                int
+	CacheNegative                 bool
+	CaseInsensitiveLookup         bool
+	MaxIndirectionDepth           int
+	JoinSeparator                 string
+	TypeOverride                  string
+	DropEmptySegments             bool
+	BoolNumericVariables          []string
+	EnableTemplates               bool
+	StrictTemplates               bool
+	SecretVariables               []string
+	RecognizeTF                   bool
+	VariableConstraints           map[string]LengthConstraint
+	EnforceConstraintsOnFetch     bool
+	NullTokens                    []string
+	HealthIncludeCacheStats       bool
+	PrefixSeparators              map[string]string
+	RejectControlChars            bool
+	InjectedVariables             map[string]string
+	InjectedVariablesOverride     bool
+}
+
+// ConditionalRequirement makes Require a required variable whenever
+// WhenVariable is set to WhenEquals
+type ConditionalRequirement struct {
+	WhenVariable string
+	WhenEquals   string
+	Require      string
+}
+
+// LengthConstraint bounds the length of a variable's value in characters.
+// Zero fields are unbounded.
+type LengthConstraint struct {
+	MinLength int
+	MaxLength int
+}
+
+// ConversionOverride replaces global conversion flags for variables with a
+// given prefix. Nil fields fall back to the global setting.
+type ConversionOverride struct {
+	EnableTypeConversion *bool
+	EnableJSONParsing    *bool
+	NullAsNull           *bool
+	EnableSizeParsing    *bool
+	StripQuotes          *bool
 }
 
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	return &Config{
-		Separator:            "_",
-		CaseTransform:        "upper",
-		Prefix:               "",
-		PrefixMode:           "prepend",
-		RequiredVariables:    []string{},
-		EnableTypeConversion: true,
-		EnableJSONParsing:    true,
+		Separator:                     "_",
+		CaseTransform:                 DefaultCaseTransform,
+		Prefix:                        "",
+		PrefixMode:                    "prepend",
+		RequiredVariables:             []string{},
+		EnableTypeConversion:          true,
+		EnableJSONParsing:             true,
+		NullAsNull:                    false,
+		EnableSizeParsing:             false,
+		JSONSchemas:                   map[string]*converter.Schema{},
+		StripQuotes:                   false,
+		ExpandReferences:              false,
+		StrictExpansion:               false,
+		MaxConcurrentFetches:          0,
+		FailOnLimit:                   false,
+		IncludeType:                   false,
+		GroupIndexed:                  false,
+		PrefixSeparator:               "",
+		SegmentTransforms:             []string{},
+		IncludeResolvedName:           false,
+		CustomConverters:              []string{},
+		PassthroughUnfiltered:         false,
+		ConversionCacheSize:           0,
+		ConditionalRequirements:       []ConditionalRequirement{},
+		BinaryEncoding:                "error",
+		JSONNumbersAsStrings:          false,
+		ScreamingChars:                resolver.DefaultScreamingChars,
+		RequestTimeoutMS:              0,
+		NewlineAsArray:                false,
+		TrimWhitespace:                false,
+		BooleanOutput:                 "bool",
+		DeprecatedVariables:           map[string]string{},
+		IncludePresent:                false,
+		EnableRelaxedJSON:             false,
+		TypeNameMap:                   map[string]string{},
+		PrefixConversionOverrides:     map[string]ConversionOverride{},
+		CollapseSeparators:            false,
+		StrictConversion:              false,
+		QuotedAsString:                false,
+		VariableMaxSizes:              nil,
+		RequiredMissingAsPrecondition: false,
+		DeclaredPaths:                 nil,
+		DeclaredPathsRequired:         false,
+		AutoPrefixSeparator:           false,
+		EnableDurationParsing:         false,
+		CamelSplit:                    false,
+		EmitConversionWarnings:        false,
+		LogFetchSuccess:               false,
+		NameCharMap:                   nil,
+		MaxArrayLength:                0,
+		CacheNegative:                 false,
+		CaseInsensitiveLookup:         false,
+		MaxIndirectionDepth:           0,
+		JoinSeparator:                 "",
+		TypeOverride:                  "",
+		DropEmptySegments:             false,
+		BoolNumericVariables:          nil,
+		EnableTemplates:               false,
+		StrictTemplates:               false,
+		SecretVariables:               nil,
+		RecognizeTF:                   false,
+		VariableConstraints:           map[string]LengthConstraint{},
+		EnforceConstraintsOnFetch:     false,
+		NullTokens:                    nil,
+		HealthIncludeCacheStats:       false,
+		PrefixSeparators:              map[string]string{},
+		RejectControlChars:            false,
+		InjectedVariables:             nil,
+		InjectedVariablesOverride:     false,
 	}
 }
 
@@ -36,10 +197,17 @@ func DefaultConfig() *Config {
 func ValidateConfig(c *Config) error {
 	// Validate case_transform
 	validCaseTransforms := map[string]bool{
-		"upper": true, "lower": true, "preserve": true,
+		"upper": true, "lower": true, "preserve": true, "screaming": true,
 	}
 	if !validCaseTransforms[c.CaseTransform] {
-		return fmt.Errorf("invalid case_transform: %s (must be upper, lower, or preserve)", c.CaseTransform)
+		return fmt.Errorf("invalid case_transform: %s (must be upper, lower, preserve, or screaming)", c.CaseTransform)
+	}
+
+	// Validate segment_transforms entries
+	for i, transform := range c.SegmentTransforms {
+		if !validCaseTransforms[transform] {
+			return fmt.Errorf("invalid segment_transforms[%d]: %s (must be upper, lower, preserve, or screaming)", i, transform)
+		}
 	}
 
 	// Validate prefix_mode
@@ -50,11 +218,37 @@ func ValidateConfig(c *Config) error {
 		return fmt.Errorf("invalid prefix_mode: %s (must be prepend or filter_only)", c.PrefixMode)
 	}
 
+	// Validate binary_encoding (empty behaves as error)
+	validBinaryEncodings := map[string]bool{
+		"": true, "base64": true, "hex": true, "error": true,
+	}
+	if !validBinaryEncodings[c.BinaryEncoding] {
+		return fmt.Errorf("invalid binary_encoding: %s (must be base64, hex, or error)", c.BinaryEncoding)
+	}
+
+	// filter_only without a prefix filters nothing and is almost certainly a mistake
+	if c.PrefixMode == "filter_only" && c.Prefix == "" {
+		return fmt.Errorf("prefix_mode filter_only requires a non-empty prefix")
+	}
+
+	// Validate boolean_output (empty behaves as bool)
+	validBooleanOutputs := map[string]bool{
+		"": true, "bool": true, "numeric": true, "string": true,
+	}
+	if !validBooleanOutputs[c.BooleanOutput] {
+		return fmt.Errorf("invalid boolean_output: %s (must be bool, numeric, or string)", c.BooleanOutput)
+	}
+
 	// Validate separator
 	if len(c.Separator) != 1 {
 		return fmt.Errorf("separator must be a single character, got: %q", c.Separator)
 	}
 
+	// Validate required_variables size
+	if len(c.RequiredVariables) > MaxRequiredVariables {
+		return fmt.Errorf("required_variables has %d entries, exceeding the maximum of %d", len(c.RequiredVariables), MaxRequiredVariables)
+	}
+
 	// Validate required_variables (non-empty strings)
 	for i, varName := range c.RequiredVariables {
 		if strings.TrimSpace(varName) == "" {
@@ -62,6 +256,151 @@ func ValidateConfig(c *Config) error {
 		}
 	}
 
+	// Validate custom_converters against the registry
+	for i, name := range c.CustomConverters {
+		if !converter.HasConverter(name) {
+			return fmt.Errorf("custom_converters[%d]: no converter registered as %q", i, name)
+		}
+	}
+
+	// Validate conditional_requirements rules
+	for i, rule := range c.ConditionalRequirements {
+		if strings.TrimSpace(rule.WhenVariable) == "" {
+			return fmt.Errorf("conditional_requirements[%d]: when_variable is empty", i)
+		}
+		if strings.TrimSpace(rule.Require) == "" {
+			return fmt.Errorf("conditional_requirements[%d]: require is empty", i)
+		}
+	}
+
+	// Validate max_concurrent_fetches (zero means unlimited)
+	if c.MaxConcurrentFetches < 0 {
+		return fmt.Errorf("max_concurrent_fetches must not be negative, got: %d", c.MaxConcurrentFetches)
+	}
+
+	// Validate request_timeout_ms (zero disables the timeout)
+	if c.RequestTimeoutMS < 0 {
+		return fmt.Errorf("request_timeout_ms must not be negative, got: %d", c.RequestTimeoutMS)
+	}
+
+	// Validate conversion_cache_size (zero disables the cache)
+	if c.ConversionCacheSize < 0 {
+		return fmt.Errorf("conversion_cache_size must not be negative, got: %d", c.ConversionCacheSize)
+	}
+
+	// Validate declared_paths entries
+	for i, path := range c.DeclaredPaths {
+		if len(path) == 0 {
+			return fmt.Errorf("declared_paths[%d] must not be empty", i)
+		}
+		for j, segment := range path {
+			if strings.TrimSpace(segment) == "" {
+				return fmt.Errorf("declared_paths[%d][%d] must not be empty", i, j)
+			}
+		}
+	}
+
+	// Validate max_indirection_depth (zero uses the default depth)
+	if c.MaxIndirectionDepth < 0 {
+		return fmt.Errorf("max_indirection_depth must not be negative, got: %d", c.MaxIndirectionDepth)
+	}
+
+	// Validate max_array_length (zero disables the limit)
+	if c.MaxArrayLength < 0 {
+		return fmt.Errorf("max_array_length must not be negative, got: %d", c.MaxArrayLength)
+	}
+
+	// Validate join_separator is a single character distinct from separator
+	if c.JoinSeparator != "" {
+		if utf8.RuneCountInString(c.JoinSeparator) != 1 {
+			return fmt.Errorf("join_separator must be a single character, got: %q", c.JoinSeparator)
+		}
+		if c.JoinSeparator == c.Separator {
+			return fmt.Errorf("join_separator must differ from separator %q", c.Separator)
+		}
+	}
+
+	// Validate name_char_map keys are single characters
+	for char := range c.NameCharMap {
+		if utf8.RuneCountInString(char) != 1 {
+			return fmt.Errorf("name_char_map keys must be a single character, got: %q", char)
+		}
+	}
+
+	// Validate null_tokens are non-empty
+	for i, token := range c.NullTokens {
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("null_tokens[%d] is empty", i)
+		}
+	}
+
+	// Validate prefix_separators entries, whose keys match case-insensitively
+	seenPrefixes := make(map[string]string, len(c.PrefixSeparators))
+	for prefix, separator := range c.PrefixSeparators {
+		if strings.TrimSpace(prefix) == "" {
+			return fmt.Errorf("prefix_separators keys must not be empty")
+		}
+		if separator == "" {
+			return fmt.Errorf("prefix_separators[%s] must not be empty", prefix)
+		}
+		if other, ok := seenPrefixes[strings.ToLower(prefix)]; ok {
+			return fmt.Errorf("prefix_separators keys %q and %q differ only in case", other, prefix)
+		}
+		seenPrefixes[strings.ToLower(prefix)] = prefix
+	}
+
+	// Validate variable_max_sizes limits
+	for name, limit := range c.VariableMaxSizes {
+		if limit <= 0 {
+			return fmt.Errorf("variable_max_sizes[%s] must be positive, got: %d", name, limit)
+		}
+	}
+
+	// Validate variable_constraints bounds
+	for name, constraint := range c.VariableConstraints {
+		if constraint.MinLength < 0 || constraint.MaxLength < 0 {
+			return fmt.Errorf("variable_constraints[%s]: lengths must not be negative", name)
+		}
+		if constraint.MaxLength > 0 && constraint.MinLength > constraint.MaxLength {
+			return fmt.Errorf("variable_constraints[%s]: min_length %d exceeds max_length %d", name, constraint.MinLength, constraint.MaxLength)
+		}
+	}
+
+	return validateConflicts(c)
+}
+
+// validateConflicts rejects combinations of options that contradict each
+// other instead of letting one silently override the other
+func validateConflicts(c *Config) error {
+	if c.PrefixMode == "filter_only" {
+		if c.PrefixSeparator != "" {
+			return fmt.Errorf("prefix_separator conflicts with prefix_mode filter_only: the prefix is never prepended")
+		}
+		if c.AutoPrefixSeparator {
+			return fmt.Errorf("auto_prefix_separator conflicts with prefix_mode filter_only: the prefix is never prepended")
+		}
+	}
+
+	if len(c.PrefixSeparators) > 0 && c.PrefixMode != "filter_only" {
+		return fmt.Errorf("prefix_separators requires prefix_mode filter_only: the prefix is not part of the path")
+	}
+
+	if c.StrictExpansion && !c.ExpandReferences {
+		return fmt.Errorf("strict_expansion requires expand_references")
+	}
+
+	if c.StrictTemplates && !c.EnableTemplates {
+		return fmt.Errorf("strict_templates requires enable_templates")
+	}
+
+	if c.DeclaredPathsRequired && len(c.DeclaredPaths) == 0 {
+		return fmt.Errorf("declared_paths_required requires a non-empty declared_paths")
+	}
+
+	if c.InjectedVariablesOverride && len(c.InjectedVariables) == 0 {
+		return fmt.Errorf("injected_variables_override requires a non-empty injected_variables")
+	}
+
 	return nil
 }
 
@@ -81,6 +420,23 @@ func getString(m *structpb.Struct, key, defaultVal string) string {
 	return strVal.StringValue
 }
 
+// getInt extracts an integer value from a protobuf Struct.
+// Fractional numbers are truncated.
+func getInt(m *structpb.Struct, key string, defaultVal int) int {
+	if m == nil || m.Fields == nil {
+		return defaultVal
+	}
+	val, ok := m.Fields[key]
+	if !ok {
+		return defaultVal
+	}
+	numVal, ok := val.Kind.(*structpb.Value_NumberValue)
+	if !ok {
+		return defaultVal
+	}
+	return int(numVal.NumberValue)
+}
+
 // getBool extracts a boolean value from a protobuf Struct
 func getBool(m *structpb.Struct, key string, defaultVal bool) bool {
 	if m == nil || m.Fields == nil {
@@ -97,6 +453,22 @@ func getBool(m *structpb.Struct, key string, defaultVal bool) bool {
 	return boolVal.BoolValue
 }
 
+// getStruct extracts a nested Struct value from a protobuf Struct
+func getStruct(m *structpb.Struct, key string) *structpb.Struct {
+	if m == nil || m.Fields == nil {
+		return nil
+	}
+	val, ok := m.Fields[key]
+	if !ok {
+		return nil
+	}
+	structVal, ok := val.Kind.(*structpb.Value_StructValue)
+	if !ok {
+		return nil
+	}
+	return structVal.StructValue
+}
+
 // getStringList extracts a string array from a protobuf Struct
 func getStringList(m *structpb.Struct, key string) []string {
 	if m == nil || m.Fields == nil {
@@ -120,3 +492,50 @@ func getStringList(m *structpb.Struct, key string) []string {
 	}
 	return result
 }
+
+// getStringMap extracts a map of string values from a nested protobuf Struct.
+// Non-string values are ignored.
+func getStringMap(m *structpb.Struct, key string) map[string]string {
+	nested := getStruct(m, key)
+	if nested == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(nested.Fields))
+	for k, val := range nested.Fields {
+		if strVal, ok := val.Kind.(*structpb.Value_StringValue); ok {
+			result[k] = strVal.StringValue
+		}
+	}
+	return result
+}
+
+// getIntMap extracts a map of integer values from a nested protobuf Struct.
+// Non-numeric entries are ignored. Returns nil when the key is absent.
+func getIntMap(m *structpb.Struct, key string) map[string]int {
+	nested := getStruct(m, key)
+	if nested == nil {
+		return nil
+	}
+
+	result := make(map[string]int, len(nested.Fields))
+	for k, val := range nested.Fields {
+		if numVal, ok := val.Kind.(*structpb.Value_NumberValue); ok {
+			result[k] = int(numVal.NumberValue)
+		}
+	}
+	return result
+}
+
+// getOptionalBool extracts a boolean value from a protobuf Struct, returning
+// nil when the key is absent or not a boolean
+func getOptionalBool(m *structpb.Struct, key string) *bool {
+	if m == nil || m.Fields == nil {
+		return nil
+	}
+	boolVal, ok := m.Fields[key].GetKind().(*structpb.Value_BoolValue)
+	if !ok {
+		return nil
+	}
+	return &boolVal.BoolValue
+}
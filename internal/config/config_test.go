@@ -1,6 +1,12 @@
 package config
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
 
 func TestConfigValidation(t *testing.T) {
 	tests := []struct {
@@ -11,6 +17,9 @@ func TestConfigValidation(t *testing.T) {
 		{"default config", DefaultConfig(), false},
 		{"invalid case_transform", &Config{Separator: "_", CaseTransform: "invalid", PrefixMode: "prepend"}, true},
 		{"invalid prefix_mode", &Config{Separator: "_", CaseTransform: "upper", PrefixMode: "invalid"}, true},
+		{"screaming case_transform", &Config{Separator: "_", CaseTransform: "screaming", PrefixMode: "prepend"}, false},
+		{"screaming segment_transforms", &Config{Separator: "_", CaseTransform: "upper", PrefixMode: "prepend", SegmentTransforms: []string{"lower", "screaming"}}, false},
+		{"unregistered custom converter", &Config{Separator: "_", CaseTransform: "upper", PrefixMode: "prepend", CustomConverters: []string{"unregistered"}}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -211,10 +220,21 @@ func TestConfigValidationComprehensive(t *testing.T) {
 			config: &Config{
 				Separator:     "_",
 				CaseTransform: "upper",
+				Prefix:        "MYAPP_",
 				PrefixMode:    "filter_only",
 			},
 			wantErr: false,
 		},
+		{
+			name: "filter_only prefix mode without prefix",
+			config: &Config{
+				Separator:     "_",
+				CaseTransform: "upper",
+				PrefixMode:    "filter_only",
+			},
+			wantErr:    true,
+			errPattern: "filter_only requires a non-empty prefix",
+		},
 		{
 			name: "empty required variable",
 			config: &Config{
@@ -503,6 +523,7 @@ func TestPrefixModeValidation(t *testing.T) {
 			cfg := &Config{
 				Separator:     "_",
 				CaseTransform: "upper",
+				Prefix:        "MYAPP_",
 				PrefixMode:    tt.prefixMode,
 			}
 			err := ValidateConfig(cfg)
@@ -573,14 +594,14 @@ func TestPrefixModeValidationWithOtherFields(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "valid filter_only mode without prefix",
+			name: "filter_only mode without prefix is rejected",
 			config: &Config{
 				Separator:     "_",
 				CaseTransform: "upper",
 				Prefix:        "",
 				PrefixMode:    "filter_only",
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
 			name: "prepend mode with lowercase transform",
@@ -662,3 +683,388 @@ func TestDefaultPrefixModeConfig(t *testing.T) {
 		t.Errorf("DefaultConfig() should be valid, got error: %v", err)
 	}
 }
+
+// Test that DefaultConfig honors the build-time DefaultCaseTransform
+func TestDefaultCaseTransform(t *testing.T) {
+	if got := DefaultConfig().CaseTransform; got != "upper" {
+		t.Errorf("DefaultConfig() CaseTransform = %q, want \"upper\"", got)
+	}
+
+	original := DefaultCaseTransform
+	t.Cleanup(func() { DefaultCaseTransform = original })
+	DefaultCaseTransform = "lower"
+
+	cfg := DefaultConfig()
+	if cfg.CaseTransform != "lower" {
+		t.Errorf("DefaultConfig() CaseTransform = %q, want \"lower\"", cfg.CaseTransform)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("DefaultConfig() should be valid, got error: %v", err)
+	}
+
+	// An explicit case_transform still wins
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{"case_transform": "upper"})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	parsed, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if parsed.CaseTransform != "upper" {
+		t.Errorf("ParseConfig() CaseTransform = %q, want \"upper\"", parsed.CaseTransform)
+	}
+}
+
+func TestMaxConcurrentFetchesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		wantErr bool
+	}{
+		{"unlimited", 0, false},
+		{"positive limit", 10, false},
+		{"negative limit", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxConcurrentFetches = tt.limit
+			err := ValidateConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseConditionalRequirements(t *testing.T) {
+	valid, err := structpb.NewStruct(map[string]interface{}{
+		"conditional_requirements": []interface{}{
+			map[string]interface{}{"when_variable": "STORAGE", "when_equals": "s3", "require": "S3_BUCKET"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(valid)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	want := ConditionalRequirement{WhenVariable: "STORAGE", WhenEquals: "s3", Require: "S3_BUCKET"}
+	if len(cfg.ConditionalRequirements) != 1 || cfg.ConditionalRequirements[0] != want {
+		t.Errorf("got %+v, want [%+v]", cfg.ConditionalRequirements, want)
+	}
+
+	for _, bad := range []interface{}{"STORAGE", []interface{}{"STORAGE"}} {
+		invalid, err := structpb.NewStruct(map[string]interface{}{"conditional_requirements": bad})
+		if err != nil {
+			t.Fatalf("failed to create config: %v", err)
+		}
+		if _, err := ParseConfig(invalid); err == nil {
+			t.Errorf("expected error for conditional_requirements %v", bad)
+		}
+	}
+
+	missingRequire := DefaultConfig()
+	missingRequire.ConditionalRequirements = []ConditionalRequirement{{WhenVariable: "STORAGE", WhenEquals: "s3"}}
+	if err := ValidateConfig(missingRequire); err == nil {
+		t.Error("expected validation error for rule without require")
+	}
+}
+
+func TestRequiredVariablesLimit(t *testing.T) {
+	makeVars := func(n int) []string {
+		vars := make([]string, n)
+		for i := range vars {
+			vars[i] = fmt.Sprintf("VAR_%d", i)
+		}
+		return vars
+	}
+
+	tests := []struct {
+		name    string
+		count   int
+		wantErr bool
+	}{
+		{"at limit", MaxRequiredVariables, false},
+		{"above limit", MaxRequiredVariables + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RequiredVariables = makeVars(tt.count)
+			err := ValidateConfig(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "exceeding the maximum of 1000") {
+				t.Errorf("expected error to name the limit, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParsePrefixConversionOverrides(t *testing.T) {
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{
+		"prefix_conversion_overrides": map[string]interface{}{
+			"APPA_": map[string]interface{}{"enable_json_parsing": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	override, ok := cfg.PrefixConversionOverrides["APPA_"]
+	if !ok {
+		t.Fatal("expected override for APPA_")
+	}
+	if override.EnableJSONParsing == nil || !*override.EnableJSONParsing {
+		t.Errorf("expected enable_json_parsing override true, got %v", override.EnableJSONParsing)
+	}
+	if override.EnableTypeConversion != nil {
+		t.Errorf("expected unset enable_type_conversion to fall back, got %v", *override.EnableTypeConversion)
+	}
+
+	invalid, err := structpb.NewStruct(map[string]interface{}{
+		"prefix_conversion_overrides": map[string]interface{}{"APPA_": true},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := ParseConfig(invalid); err == nil {
+		t.Error("expected error for non-object override")
+	}
+
+	unknown, err := structpb.NewStruct(map[string]interface{}{
+		"prefix_conversion_overrides": map[string]interface{}{
+			"APPA_": map[string]interface{}{"enable_json_parsing": true, "enable_json": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	_, err = ParseConfig(unknown)
+	if err == nil {
+		t.Fatal("expected error for unknown override option")
+	}
+	if !strings.Contains(err.Error(), "APPA_") || !strings.Contains(err.Error(), `"enable_json"`) {
+		t.Errorf("expected error naming the prefix and option, got %v", err)
+	}
+}
+
+func TestParseVariableMaxSizes(t *testing.T) {
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{
+		"variable_max_sizes": map[string]interface{}{"API_TOKEN": 64},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if got := cfg.VariableMaxSizes["API_TOKEN"]; got != 64 {
+		t.Errorf("VariableMaxSizes[API_TOKEN] = %d, want 64", got)
+	}
+
+	invalid, err := structpb.NewStruct(map[string]interface{}{
+		"variable_max_sizes": map[string]interface{}{"API_TOKEN": 0},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err = ParseConfig(invalid)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("expected error for non-positive limit")
+	}
+}
+
+func TestParseNullTokens(t *testing.T) {
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{
+		"null_tokens": []interface{}{"none", "nil"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if got := cfg.NullTokens; len(got) != 2 || got[0] != "none" || got[1] != "nil" {
+		t.Errorf("NullTokens = %v, want [none nil]", got)
+	}
+
+	cfg.NullTokens = []string{"none", " "}
+	if err := ValidateConfig(cfg); err == nil {
+		t.Error("expected error for empty null token")
+	}
+}
+
+func TestParseVariableConstraints(t *testing.T) {
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{
+		"variable_constraints": map[string]interface{}{
+			"API_KEY": map[string]interface{}{"min_length": 32},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if got, want := cfg.VariableConstraints["API_KEY"], (LengthConstraint{MinLength: 32}); got != want {
+		t.Errorf("VariableConstraints[API_KEY] = %+v, want %+v", got, want)
+	}
+
+	invalid := []LengthConstraint{{MinLength: -1}, {MinLength: 10, MaxLength: 5}}
+	for _, constraint := range invalid {
+		cfg := DefaultConfig()
+		cfg.VariableConstraints = map[string]LengthConstraint{"API_KEY": constraint}
+		if err := ValidateConfig(cfg); err == nil {
+			t.Errorf("expected error for constraint %+v", constraint)
+		}
+	}
+}
+
+func TestParseDeclaredPaths(t *testing.T) {
+	pbConfig, err := structpb.NewStruct(map[string]interface{}{
+		"declared_paths": []interface{}{[]interface{}{"database", "host"}, "API_KEY"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg, err := ParseConfig(pbConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	want := [][]string{{"database", "host"}, {"API_KEY"}}
+	if fmt.Sprint(cfg.DeclaredPaths) != fmt.Sprint(want) {
+		t.Errorf("DeclaredPaths = %v, want %v", cfg.DeclaredPaths, want)
+	}
+
+	invalid, err := structpb.NewStruct(map[string]interface{}{
+		"declared_paths": []interface{}{42},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := ParseConfig(invalid); err == nil {
+		t.Error("expected error for non-path entry")
+	}
+}
+
+func TestConflictingOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*Config)
+		errPattern string
+	}{
+		{
+			name: "prefix_separator with filter_only",
+			modify: func(c *Config) {
+				c.Prefix, c.PrefixMode, c.PrefixSeparator = "MYAPP", "filter_only", "_"
+			},
+			errPattern: "prefix_separator conflicts with prefix_mode filter_only",
+		},
+		{
+			name: "auto_prefix_separator with filter_only",
+			modify: func(c *Config) {
+				c.Prefix, c.PrefixMode, c.AutoPrefixSeparator = "MYAPP", "filter_only", true
+			},
+			errPattern: "auto_prefix_separator conflicts with prefix_mode filter_only",
+		},
+		{
+			name:       "prefix_separators without filter_only",
+			modify:     func(c *Config) { c.PrefixSeparators = map[string]string{"app": "-"} },
+			errPattern: "prefix_separators requires prefix_mode filter_only",
+		},
+		{
+			name: "prefix_separators keys differing only in case",
+			modify: func(c *Config) {
+				c.Prefix, c.PrefixMode = "APP", "filter_only"
+				c.PrefixSeparators = map[string]string{"app": "-", "APP": "_"}
+			},
+			errPattern: "differ only in case",
+		},
+		{
+			name:       "strict_expansion without expand_references",
+			modify:     func(c *Config) { c.StrictExpansion = true },
+			errPattern: "strict_expansion requires expand_references",
+		},
+		{
+			name:       "strict_templates without enable_templates",
+			modify:     func(c *Config) { c.StrictTemplates = true },
+			errPattern: "strict_templates requires enable_templates",
+		},
+		{
+			name:       "declared_paths_required without declared_paths",
+			modify:     func(c *Config) { c.DeclaredPathsRequired = true },
+			errPattern: "declared_paths_required requires a non-empty declared_paths",
+		},
+		{
+			name:       "injected_variables_override without injected_variables",
+			modify:     func(c *Config) { c.InjectedVariablesOverride = true },
+			errPattern: "injected_variables_override requires a non-empty injected_variables",
+		},
+		{
+			name: "compatible options",
+			modify: func(c *Config) {
+				c.Prefix, c.PrefixSeparator, c.AutoPrefixSeparator = "MYAPP", "_", true
+				c.ExpandReferences, c.StrictExpansion = true, true
+				c.EnableTemplates, c.StrictTemplates = true, true
+				c.DeclaredPaths, c.DeclaredPathsRequired = [][]string{{"host"}}, true
+				c.InjectedVariables, c.InjectedVariablesOverride = map[string]string{"HOST": "localhost"}, true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := ValidateConfig(cfg)
+			if tt.errPattern == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errPattern) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.errPattern)
+			}
+		})
+	}
+}
+
+func TestJoinSeparatorValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		joinSeparator string
+		wantErr       bool
+	}{
+		{"unset", "", false},
+		{"slash", "/", false},
+		{"same as separator", "_", true},
+		{"multiple characters", "::", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.JoinSeparator = tt.joinSeparator
+			if err := ValidateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,9 +1,26 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
 	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
 )
 
+// conversionOverrideKeys are the conversion flags a prefix_conversion_overrides
+// entry may set
+var conversionOverrideKeys = []string{
+	"enable_type_conversion",
+	"enable_json_parsing",
+	"null_as_null",
+	"enable_size_parsing",
+	"strip_quotes",
+}
+
 // ParseConfig parses a protobuf Struct into a Config
 func ParseConfig(pbConfig *structpb.Struct) (*Config, error) {
 	cfg := DefaultConfig()
@@ -17,13 +34,254 @@ func ParseConfig(pbConfig *structpb.Struct) (*Config, error) {
 	cfg.CaseTransform = getString(pbConfig, "case_transform", cfg.CaseTransform)
 	cfg.Prefix = getString(pbConfig, "prefix", cfg.Prefix)
 	cfg.PrefixMode = getString(pbConfig, "prefix_mode", cfg.PrefixMode)
+	cfg.PrefixSeparator = getString(pbConfig, "prefix_separator", cfg.PrefixSeparator)
 	cfg.EnableTypeConversion = getBool(pbConfig, "enable_type_conversion", cfg.EnableTypeConversion)
 	cfg.EnableJSONParsing = getBool(pbConfig, "enable_json_parsing", cfg.EnableJSONParsing)
+	cfg.NullAsNull = getBool(pbConfig, "null_as_null", cfg.NullAsNull)
+	cfg.EnableSizeParsing = getBool(pbConfig, "enable_size_parsing", cfg.EnableSizeParsing)
+	cfg.StripQuotes = getBool(pbConfig, "strip_quotes", cfg.StripQuotes)
+	cfg.ExpandReferences = getBool(pbConfig, "expand_references", cfg.ExpandReferences)
+	cfg.StrictExpansion = getBool(pbConfig, "strict_expansion", cfg.StrictExpansion)
+	cfg.MaxConcurrentFetches = getInt(pbConfig, "max_concurrent_fetches", cfg.MaxConcurrentFetches)
+	cfg.FailOnLimit = getBool(pbConfig, "fail_on_limit", cfg.FailOnLimit)
+	cfg.IncludeType = getBool(pbConfig, "include_type", cfg.IncludeType)
+	cfg.GroupIndexed = getBool(pbConfig, "group_indexed", cfg.GroupIndexed)
+	cfg.IncludeResolvedName = getBool(pbConfig, "include_resolved_name", cfg.IncludeResolvedName)
+	cfg.PassthroughUnfiltered = getBool(pbConfig, "passthrough_unfiltered", cfg.PassthroughUnfiltered)
+	cfg.ConversionCacheSize = getInt(pbConfig, "conversion_cache_size", cfg.ConversionCacheSize)
+	cfg.BinaryEncoding = getString(pbConfig, "binary_encoding", cfg.BinaryEncoding)
+	cfg.JSONNumbersAsStrings = getBool(pbConfig, "json_numbers_as_strings", cfg.JSONNumbersAsStrings)
+	cfg.ScreamingChars = getString(pbConfig, "screaming_chars", cfg.ScreamingChars)
+	cfg.RequestTimeoutMS = getInt(pbConfig, "request_timeout_ms", cfg.RequestTimeoutMS)
+	cfg.NewlineAsArray = getBool(pbConfig, "newline_as_array", cfg.NewlineAsArray)
+	cfg.TrimWhitespace = getBool(pbConfig, "trim_whitespace", cfg.TrimWhitespace)
+	cfg.BooleanOutput = getString(pbConfig, "boolean_output", cfg.BooleanOutput)
+	cfg.IncludePresent = getBool(pbConfig, "include_present", cfg.IncludePresent)
+	cfg.EnableRelaxedJSON = getBool(pbConfig, "enable_relaxed_json", cfg.EnableRelaxedJSON)
+	cfg.CollapseSeparators = getBool(pbConfig, "collapse_separators", cfg.CollapseSeparators)
+	cfg.StrictConversion = getBool(pbConfig, "strict_conversion", cfg.StrictConversion)
+	cfg.QuotedAsString = getBool(pbConfig, "quoted_as_string", cfg.QuotedAsString)
+	cfg.RequiredMissingAsPrecondition = getBool(pbConfig, "required_missing_as_precondition", cfg.RequiredMissingAsPrecondition)
+	cfg.DeclaredPathsRequired = getBool(pbConfig, "declared_paths_required", cfg.DeclaredPathsRequired)
+	cfg.AutoPrefixSeparator = getBool(pbConfig, "auto_prefix_separator", cfg.AutoPrefixSeparator)
+	cfg.EnableDurationParsing = getBool(pbConfig, "enable_duration_parsing", cfg.EnableDurationParsing)
+	cfg.CamelSplit = getBool(pbConfig, "camel_split", cfg.CamelSplit)
+	cfg.EmitConversionWarnings = getBool(pbConfig, "emit_conversion_warnings", cfg.EmitConversionWarnings)
+	cfg.LogFetchSuccess = getBool(pbConfig, "log_fetch_success", cfg.LogFetchSuccess)
+	cfg.MaxArrayLength = getInt(pbConfig, "max_array_length", cfg.MaxArrayLength)
+	cfg.CacheNegative = getBool(pbConfig, "cache_negative", cfg.CacheNegative)
+	cfg.CaseInsensitiveLookup = getBool(pbConfig, "case_insensitive_lookup", cfg.CaseInsensitiveLookup)
+	cfg.MaxIndirectionDepth = getInt(pbConfig, "max_indirection_depth", cfg.MaxIndirectionDepth)
+	cfg.JoinSeparator = getString(pbConfig, "join_separator", cfg.JoinSeparator)
+	cfg.TypeOverride = getString(pbConfig, "type_override", cfg.TypeOverride)
+	cfg.DropEmptySegments = getBool(pbConfig, "drop_empty_segments", cfg.DropEmptySegments)
+	cfg.EnableTemplates = getBool(pbConfig, "enable_templates", cfg.EnableTemplates)
+	cfg.StrictTemplates = getBool(pbConfig, "strict_templates", cfg.StrictTemplates)
+	cfg.RecognizeTF = getBool(pbConfig, "recognize_tf", cfg.RecognizeTF)
+	cfg.EnforceConstraintsOnFetch = getBool(pbConfig, "enforce_constraints_on_fetch", cfg.EnforceConstraintsOnFetch)
+	cfg.HealthIncludeCacheStats = getBool(pbConfig, "health_include_cache_stats", cfg.HealthIncludeCacheStats)
+	cfg.RejectControlChars = getBool(pbConfig, "reject_control_chars", cfg.RejectControlChars)
+	cfg.InjectedVariablesOverride = getBool(pbConfig, "injected_variables_override", cfg.InjectedVariablesOverride)
 
 	// Parse required_variables list
 	if requiredVars := getStringList(pbConfig, "required_variables"); requiredVars != nil {
 		cfg.RequiredVariables = requiredVars
 	}
 
+	// Parse segment_transforms list
+	if segmentTransforms := getStringList(pbConfig, "segment_transforms"); segmentTransforms != nil {
+		cfg.SegmentTransforms = segmentTransforms
+	}
+
+	// Parse custom_converters list
+	if customConverters := getStringList(pbConfig, "custom_converters"); customConverters != nil {
+		cfg.CustomConverters = customConverters
+	}
+
+	// Parse bool_numeric_variables list
+	if boolNumeric := getStringList(pbConfig, "bool_numeric_variables"); boolNumeric != nil {
+		cfg.BoolNumericVariables = boolNumeric
+	}
+
+	// Parse secret_variables list
+	if secrets := getStringList(pbConfig, "secret_variables"); secrets != nil {
+		cfg.SecretVariables = secrets
+	}
+
+	// Parse null_tokens list
+	if nullTokens := getStringList(pbConfig, "null_tokens"); nullTokens != nil {
+		cfg.NullTokens = nullTokens
+	}
+
+	// Parse deprecated_variables map
+	if deprecated := getStringMap(pbConfig, "deprecated_variables"); deprecated != nil {
+		cfg.DeprecatedVariables = deprecated
+	}
+
+	// Parse type_name_map map
+	if typeNames := getStringMap(pbConfig, "type_name_map"); typeNames != nil {
+		cfg.TypeNameMap = typeNames
+	}
+
+	// Parse prefix_separators map of leading path segment to separator
+	if prefixSeparators := getStringMap(pbConfig, "prefix_separators"); prefixSeparators != nil {
+		cfg.PrefixSeparators = prefixSeparators
+	}
+
+	// Parse injected_variables map of variable name to value
+	if injected := getStringMap(pbConfig, "injected_variables"); injected != nil {
+		cfg.InjectedVariables = injected
+	}
+
+	// Parse name_char_map map of character to replacement
+	if nameChars := getStringMap(pbConfig, "name_char_map"); nameChars != nil {
+		cfg.NameCharMap = nameChars
+	}
+
+	// Parse variable_max_sizes map of variable name to byte limit
+	if maxSizes := getIntMap(pbConfig, "variable_max_sizes"); maxSizes != nil {
+		cfg.VariableMaxSizes = maxSizes
+	}
+
+	// Parse prefix_conversion_overrides map of prefix to conversion flags
+	if overrides := getStruct(pbConfig, "prefix_conversion_overrides"); overrides != nil {
+		for prefix, val := range overrides.Fields {
+			flags := val.GetStructValue()
+			if flags == nil {
+				return nil, fmt.Errorf("prefix_conversion_overrides[%s]: must be an object", prefix)
+			}
+			for _, key := range slices.Sorted(maps.Keys(flags.Fields)) {
+				if !slices.Contains(conversionOverrideKeys, key) {
+					return nil, fmt.Errorf("prefix_conversion_overrides[%s]: unknown option %q (supported: %v)", prefix, key, conversionOverrideKeys)
+				}
+			}
+			cfg.PrefixConversionOverrides[prefix] = ConversionOverride{
+				EnableTypeConversion: getOptionalBool(flags, "enable_type_conversion"),
+				EnableJSONParsing:    getOptionalBool(flags, "enable_json_parsing"),
+				NullAsNull:           getOptionalBool(flags, "null_as_null"),
+				EnableSizeParsing:    getOptionalBool(flags, "enable_size_parsing"),
+				StripQuotes:          getOptionalBool(flags, "strip_quotes"),
+			}
+		}
+	}
+
+	// Parse variable_constraints map of variable name to length bounds
+	if constraints := getStruct(pbConfig, "variable_constraints"); constraints != nil {
+		for varName, val := range constraints.Fields {
+			bounds := val.GetStructValue()
+			if bounds == nil {
+				return nil, fmt.Errorf("variable_constraints[%s]: must be an object", varName)
+			}
+			cfg.VariableConstraints[varName] = LengthConstraint{
+				MinLength: getInt(bounds, "min_length", 0),
+				MaxLength: getInt(bounds, "max_length", 0),
+			}
+		}
+	}
+
+	// Parse conditional_requirements list
+	if rules, ok := pbConfig.GetFields()["conditional_requirements"]; ok {
+		parsed, err := parseConditionalRequirements(rules)
+		if err != nil {
+			return nil, fmt.Errorf("conditional_requirements: %w", err)
+		}
+		cfg.ConditionalRequirements = parsed
+	}
+
+	// Parse declared_paths list
+	if paths, ok := pbConfig.GetFields()["declared_paths"]; ok {
+		parsed, err := parseDeclaredPaths(paths)
+		if err != nil {
+			return nil, fmt.Errorf("declared_paths: %w", err)
+		}
+		cfg.DeclaredPaths = parsed
+	}
+
+	// Parse json_schemas map (schema documents may be objects or JSON strings),
+	// rejecting unsupported keywords and compiling patterns once
+	if schemas := getStruct(pbConfig, "json_schemas"); schemas != nil {
+		for varName, val := range schemas.Fields {
+			doc, err := parseSchema(val)
+			if err != nil {
+				return nil, fmt.Errorf("json_schemas[%s]: %w", varName, err)
+			}
+			schema, err := converter.CompileSchema(doc)
+			if err != nil {
+				return nil, fmt.Errorf("json_schemas[%s]: %w", varName, err)
+			}
+			cfg.JSONSchemas[varName] = schema
+		}
+	}
+
 	return cfg, nil
 }
+
+// parseSchema converts a schema config value into a schema document.
+// Accepts either an object or a string containing a JSON object.
+func parseSchema(val *structpb.Value) (map[string]interface{}, error) {
+	switch kind := val.Kind.(type) {
+	case *structpb.Value_StructValue:
+		return kind.StructValue.AsMap(), nil
+	case *structpb.Value_StringValue:
+		var schema map[string]interface{}
+		if err := json.Unmarshal([]byte(kind.StringValue), &schema); err != nil {
+			return nil, fmt.Errorf("invalid schema document: %w", err)
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("schema must be an object or a JSON string")
+	}
+}
+
+// parseDeclaredPaths parses a list of paths, each given as a list of
+// segments or, for single-segment paths, a plain string.
+func parseDeclaredPaths(val *structpb.Value) ([][]string, error) {
+	list := val.GetListValue()
+	if list == nil {
+		return nil, fmt.Errorf("must be a list of paths")
+	}
+
+	paths := make([][]string, 0, len(list.Values))
+	for i, item := range list.Values {
+		switch kind := item.GetKind().(type) {
+		case *structpb.Value_StringValue:
+			paths = append(paths, []string{kind.StringValue})
+		case *structpb.Value_ListValue:
+			path := make([]string, 0, len(kind.ListValue.Values))
+			for j, segment := range kind.ListValue.Values {
+				str, ok := segment.GetKind().(*structpb.Value_StringValue)
+				if !ok {
+					return nil, fmt.Errorf("[%d][%d] must be a string", i, j)
+				}
+				path = append(path, str.StringValue)
+			}
+			paths = append(paths, path)
+		default:
+			return nil, fmt.Errorf("[%d] must be a string or a list of strings", i)
+		}
+	}
+	return paths, nil
+}
+
+// parseConditionalRequirements converts a list of
+// {when_variable, when_equals, require} objects into rules
+func parseConditionalRequirements(val *structpb.Value) ([]ConditionalRequirement, error) {
+	list := val.GetListValue()
+	if list == nil {
+		return nil, fmt.Errorf("must be a list of objects")
+	}
+
+	rules := make([]ConditionalRequirement, 0, len(list.Values))
+	for i, item := range list.Values {
+		rule := item.GetStructValue()
+		if rule == nil {
+			return nil, fmt.Errorf("[%d] must be an object", i)
+		}
+		rules = append(rules, ConditionalRequirement{
+			WhenVariable: getString(rule, "when_variable", ""),
+			WhenEquals:   getString(rule, "when_equals", ""),
+			Require:      getString(rule, "require", ""),
+		})
+	}
+	return rules, nil
+}
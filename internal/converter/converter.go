@@ -3,6 +3,8 @@ package converter
 
 import (
 	"errors"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -10,23 +12,110 @@ import (
 var (
 	// ErrValueTooLarge is returned when the value exceeds maximum size
 	ErrValueTooLarge = errors.New("value exceeds maximum size of 1MB")
+	// ErrLossyConversion is returned in strict mode when an integer cannot be
+	// represented exactly as float64
+	ErrLossyConversion = errors.New("integer exceeds the exactly representable range of float64")
+	// ErrConversion is the class reported by ErrorClass for unrecognized errors
+	ErrConversion = errors.New("conversion failed")
 )
 
+// ErrorClass returns the sentinel error that err wraps, dropping any detail
+// (such as JSON syntax errors) that may quote part of the converted value.
+// Errors wrapping no known sentinel are reported as ErrConversion.
+func ErrorClass(err error) error {
+	for _, class := range []error{ErrValueTooLarge, ErrLossyConversion, ErrInvalidJSON, ErrJSONTooDeep, ErrArrayTooLong} {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+	return ErrConversion
+}
+
 const (
 	// MaxValueSize is the maximum allowed size for a value (1MB)
 	MaxValueSize = 1 * 1024 * 1024
 )
 
+// Options controls which conversions ConvertValueWithOptions attempts.
+type Options struct {
+	// EnableTypeConversion enables number and boolean detection.
+	EnableTypeConversion bool
+	// EnableJSONParsing enables parsing of JSON objects and arrays.
+	EnableJSONParsing bool
+	// NullAsNull maps the case-insensitive tokens "null" and "nil" to a nil value.
+	// Only applies when EnableTypeConversion is set.
+	NullAsNull bool
+	// EnableSizeParsing converts size values such as "10MB" to a byte count.
+	// Only applies when EnableTypeConversion is set.
+	EnableSizeParsing bool
+	// EnableDurationParsing converts Go ("1h30m") and ISO 8601 ("PT1H30M")
+	// durations to a number of seconds.
+	// Only applies when EnableTypeConversion is set.
+	EnableDurationParsing bool
+	// StripQuotes removes a single matching pair of surrounding single or double
+	// quotes from values that are not parsed as JSON.
+	StripQuotes bool
+	// QuotedAsString returns values wrapped in double quotes as the unquoted
+	// string, skipping all further conversion.
+	QuotedAsString bool
+	// JSONNumbersAsStrings keeps numbers inside parsed JSON as strings with
+	// their exact literal instead of float64.
+	JSONNumbersAsStrings bool
+	// TrimWhitespace removes leading and trailing whitespace before any other
+	// conversion, so whitespace-only values become empty strings.
+	TrimWhitespace bool
+	// EnableRelaxedJSON retries JSON that fails strict parsing after removing
+	// comments and trailing commas (see RelaxJSON).
+	EnableRelaxedJSON bool
+	// StrictConversion fails with ErrLossyConversion instead of returning an
+	// integer that float64 cannot represent exactly (beyond ±2^53).
+	StrictConversion bool
+	// NewlineAsArray splits multi-line values that are not JSON into an array
+	// of trimmed strings, skipping blank lines.
+	NewlineAsArray bool
+	// NumericBooleans converts "0" and "1" to booleans instead of numbers.
+	// Only applies when EnableTypeConversion is set.
+	NumericBooleans bool
+	// RecognizeTF converts the single letters "t" and "f" (case-insensitive)
+	// to booleans, as in Postgres output. "0" and "1" remain numbers unless
+	// NumericBooleans is set. Only applies when EnableTypeConversion is set.
+	RecognizeTF bool
+	// MaxArrayLength, when positive, fails with ErrArrayTooLong if a parsed
+	// JSON array (at any nesting level) or a split multi-line value has more
+	// elements.
+	MaxArrayLength int
+	// CustomConverters names registered converters (see RegisterConverter) tried
+	// in order before the built-in scalar conversions.
+	CustomConverters []string
+}
+
 // ConvertValue applies automatic type conversion to a string value.
 // Conversion precedence: JSON (if starts with { or [) → Number → Boolean → String.
 // enableTypeConversion controls number/boolean conversion, enableJSONParsing controls JSON parsing.
 // Returns the converted value as interface{}, type string, and error if conversion fails.
 func ConvertValue(value string, enableTypeConversion, enableJSONParsing bool) (result interface{}, typeStr string, err error) {
+	return ConvertValueWithOptions(value, Options{
+		EnableTypeConversion: enableTypeConversion,
+		EnableJSONParsing:    enableJSONParsing,
+	})
+}
+
+// ConvertValueWithOptions applies automatic type conversion to a string value
+// using the conversions enabled in opts.
+// Conversion precedence: JSON (if starts with { or [) → Quoted → Lines → Custom → Number → Size → Duration → Null → Boolean → String.
+// The type string is one of "string", "integer", "float", "boolean", "null", "object" or "array".
+// Returns the converted value as interface{}, type string, and error if conversion fails.
+func ConvertValueWithOptions(value string, opts Options) (result interface{}, typeStr string, err error) {
 	// Check size limit
 	if len(value) > MaxValueSize {
 		return nil, "", ErrValueTooLarge
 	}
 
+	// Trim surrounding whitespace
+	if opts.TrimWhitespace {
+		value = strings.TrimSpace(value)
+	}
+
 	// Empty strings remain empty strings
 	if value == "" {
 		return value, "string", nil
@@ -34,11 +123,19 @@ func ConvertValue(value string, enableTypeConversion, enableJSONParsing bool) (r
 
 	// Check JSON parsing first (if enabled and value starts with { or [)
 	trimmed := strings.TrimSpace(value)
-	if enableJSONParsing && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
-		result, err := TryJSON(value)
+	if opts.EnableJSONParsing && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+		result, err := TryJSONWithOptions(value, opts.JSONNumbersAsStrings)
+		if err != nil && opts.EnableRelaxedJSON && errors.Is(err, ErrInvalidJSON) {
+			if relaxed, relaxedErr := TryJSONWithOptions(RelaxJSON(value), opts.JSONNumbersAsStrings); relaxedErr == nil {
+				result, err = relaxed, nil
+			}
+		}
 		if err != nil {
 			return nil, "", err
 		}
+		if err := ValidateArrayLength(result, opts.MaxArrayLength); err != nil {
+			return nil, "", err
+		}
 		// Determine type from result
 		typ := "object"
 		if _, isArray := result.([]interface{}); isArray {
@@ -47,20 +144,79 @@ func ConvertValue(value string, enableTypeConversion, enableJSONParsing bool) (r
 		return result, typ, nil
 	}
 
+	// Double-quoted values are intentionally typed strings
+	if opts.QuotedAsString && len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1], "string", nil
+	}
+
+	// Strip surrounding quotes (never applied to JSON values handled above)
+	if opts.StripQuotes {
+		value = StripQuotes(value)
+	}
+
+	// Split multi-line values into an array of lines
+	if opts.NewlineAsArray && strings.Contains(value, "\n") {
+		lines := SplitLines(value)
+		if err := ValidateArrayLength(lines, opts.MaxArrayLength); err != nil {
+			return nil, "", err
+		}
+		return lines, "array", nil
+	}
+
 	// Skip type conversion if disabled
-	if !enableTypeConversion {
+	if !opts.EnableTypeConversion {
 		return value, "string", nil
 	}
 
+	// Try explicitly configured custom converters
+	if len(opts.CustomConverters) > 0 {
+		if result, typ, ok := tryCustom(value, opts.CustomConverters); ok {
+			return result, typ, nil
+		}
+	}
+
+	// Numeric booleans take precedence over numbers when requested
+	if opts.NumericBooleans && (value == "0" || value == "1") {
+		return value == "1", "boolean", nil
+	}
+
 	// Try numeric conversion
 	if num, ok := TryNumeric(value); ok {
-		return num, "number", nil
+		typ := NumberType(value, num)
+		if opts.StrictConversion && typ == "integer" && !IsExactInteger(value) {
+			return nil, "", ErrLossyConversion
+		}
+		return num, typ, nil
+	}
+
+	// Try size conversion
+	if opts.EnableSizeParsing {
+		if size, ok := TrySize(value); ok {
+			return size, NumberType("", size), nil
+		}
+	}
+
+	// Try duration conversion
+	if opts.EnableDurationParsing {
+		if seconds, ok := TryDuration(value); ok {
+			return seconds, NumberType("", seconds), nil
+		}
+	}
+
+	// Try null conversion
+	if opts.NullAsNull && IsNull(value) {
+		return nil, "null", nil
 	}
 
 	// Try boolean conversion
 	if b, ok := TryBoolean(value); ok {
 		return b, "boolean", nil
 	}
+	if opts.RecognizeTF {
+		if b, ok := TryTFBoolean(value); ok {
+			return b, "boolean", nil
+		}
+	}
 
 	// Default to string
 	return value, "string", nil
@@ -78,6 +234,37 @@ func TryNumeric(value string) (float64, bool) {
 	return f, true
 }
 
+// NumberType returns the type string for a parsed number: "integer" when the
+// literal has no fractional part or exponent, "float" otherwise. The returned
+// Go value is always float64; this only refines the reported type. When literal
+// is empty (e.g. derived values such as sizes), the value itself is inspected.
+func NumberType(literal string, num float64) string {
+	if math.IsInf(num, 0) || math.IsNaN(num) {
+		return "float"
+	}
+	if literal == "" {
+		if num == math.Trunc(num) {
+			return "integer"
+		}
+		return "float"
+	}
+	if strings.ContainsAny(literal, ".eEpP") {
+		return "float"
+	}
+	return "integer"
+}
+
+// IsExactInteger reports whether the integer literal is represented exactly
+// when parsed as float64. Literals that are not base-10 integers report false.
+func IsExactInteger(literal string) bool {
+	n, ok := new(big.Int).SetString(literal, 10)
+	if !ok {
+		return false
+	}
+	_, accuracy := new(big.Float).SetInt(n).Float64()
+	return accuracy == big.Exact
+}
+
 // TryBoolean attempts to parse a boolean value.
 // Supports: true, false, yes, no (case-insensitive).
 // Returns the boolean value and true if successful, false and false otherwise.
@@ -93,3 +280,54 @@ func TryBoolean(value string) (result, ok bool) {
 		return false, false
 	}
 }
+
+// TryTFBoolean attempts to parse a Postgres-style boolean: exactly one "t" or
+// "f" (case-insensitive), so that longer values such as "tf" stay strings.
+// Returns the boolean value and true if successful, false and false otherwise.
+func TryTFBoolean(value string) (result, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "t":
+		return true, true
+	case "f":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// IsNull reports whether a value is a null token.
+// Supports: null, nil (case-insensitive).
+func IsNull(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "null", "nil":
+		return true
+	default:
+		return false
+	}
+}
+
+// StripQuotes removes a single pair of matching leading and trailing quotes
+// (either single or double) from a value. Values without a matching pair are
+// returned unchanged.
+func StripQuotes(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	first, last := value[0], value[len(value)-1]
+	if first == last && (first == '"' || first == '\'') {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// SplitLines splits value on newlines into trimmed strings, skipping blank lines.
+func SplitLines(value string) []interface{} {
+	lines := strings.Split(value, "\n")
+	result := make([]interface{}, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
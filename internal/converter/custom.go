@@ -0,0 +1,85 @@
+package converter
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConverterFunc converts a raw string to a value. It returns false when the
+// value is not in the format it handles. Returned values must be one of the
+// types produced by the built-in converters: string, float64, bool, nil,
+// map[string]interface{} or []interface{}.
+type ConverterFunc func(string) (interface{}, bool)
+
+var (
+	customMu         sync.RWMutex
+	customConverters = map[string]ConverterFunc{}
+	customGeneration atomic.Uint64
+)
+
+// RegisterConverter registers fn under name so it can be selected with the
+// custom_converters option. Registering an existing name replaces it. The
+// registry is process-wide and only reachable from code compiled into the
+// provider binary, typically an init function in a fork's main package.
+func RegisterConverter(name string, fn ConverterFunc) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	customConverters[name] = fn
+	customGeneration.Add(1)
+}
+
+// UnregisterConverter removes the converter registered under name, if any.
+func UnregisterConverter(name string) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	delete(customConverters, name)
+	customGeneration.Add(1)
+}
+
+// RegistryGeneration returns a counter that changes whenever a converter is
+// registered or unregistered, so cached conversion results can be keyed on it.
+func RegistryGeneration() uint64 {
+	return customGeneration.Load()
+}
+
+// HasConverter reports whether a converter is registered under name
+func HasConverter(name string) bool {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	_, ok := customConverters[name]
+	return ok
+}
+
+// tryCustom applies the named converters in order and returns the first match
+func tryCustom(value string, names []string) (interface{}, string, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	for _, name := range names {
+		fn, ok := customConverters[name]
+		if !ok {
+			continue
+		}
+		if result, ok := fn(value); ok {
+			return result, typeOf(result), true
+		}
+	}
+	return nil, "", false
+}
+
+// typeOf returns the type string of a converted value
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return NumberType("", val)
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDateUnits and isoTimeUnits map ISO 8601 designators before and after the
+// "T" marker to seconds. Years and months are not supported since their
+// length varies.
+var (
+	isoDateUnits = map[byte]float64{'W': 7 * 24 * 3600, 'D': 24 * 3600}
+	isoTimeUnits = map[byte]float64{'H': 3600, 'M': 60, 'S': 1}
+)
+
+// TryDuration attempts to parse a Go duration such as "30s" or "1h30m", or an
+// ISO 8601 duration such as "PT30S" (see TryISODuration).
+// Returns the duration in seconds as float64 and true if successful.
+func TryDuration(value string) (float64, bool) {
+	trimmed := strings.TrimSpace(value)
+	if seconds, ok := TryISODuration(trimmed); ok {
+		return seconds, true
+	}
+	d, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, false
+	}
+	return d.Seconds(), true
+}
+
+// TryISODuration attempts to parse an ISO 8601 duration of the form
+// PnW, PnDTnHnMnS or a subset thereof, e.g. "PT1H30M" or "P1DT12H".
+// The last component may be fractional. Years and months are rejected.
+// Returns the duration in seconds as float64 and true if successful.
+func TryISODuration(value string) (float64, bool) {
+	if len(value) < 3 || value[0] != 'P' {
+		return 0, false
+	}
+
+	var total float64
+	units, inTime := isoDateUnits, false
+	components := 0
+	start := 1
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		if (c >= '0' && c <= '9') || c == '.' {
+			continue
+		}
+		if c == 'T' {
+			if inTime || i != start {
+				return 0, false
+			}
+			units, inTime = isoTimeUnits, true
+			start = i + 1
+			continue
+		}
+		unit, ok := units[c]
+		if !ok || i == start {
+			return 0, false
+		}
+		num, err := strconv.ParseFloat(value[start:i], 64)
+		if err != nil {
+			return 0, false
+		}
+		total += num * unit
+		components++
+		start = i + 1
+	}
+
+	// Reject trailing digits without a designator and a "T" without components
+	if start != len(value) || components == 0 || value[len(value)-1] == 'T' {
+		return 0, false
+	}
+	return total, true
+}
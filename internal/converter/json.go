@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 )
 
 var (
@@ -11,6 +13,8 @@ var (
 	ErrInvalidJSON = errors.New("invalid JSON")
 	// ErrJSONTooDeep is returned when JSON nesting exceeds max depth
 	ErrJSONTooDeep = errors.New("JSON nesting depth exceeds maximum of 100 levels")
+	// ErrArrayTooLong is returned when an array exceeds the configured maximum length
+	ErrArrayTooLong = errors.New("array length exceeds maximum")
 )
 
 const (
@@ -22,10 +26,26 @@ const (
 // Returns the parsed value (map[string]interface{} for objects, []interface{} for arrays).
 // Returns error if parsing fails or depth exceeds limit.
 func TryJSON(value string) (interface{}, error) {
+	return TryJSONWithOptions(value, false)
+}
+
+// TryJSONWithOptions parses a JSON string like TryJSON. When numbersAsStrings
+// is set, JSON numbers are returned as strings holding their exact literal
+// (e.g. account numbers or IDs beyond float64 precision) instead of float64.
+func TryJSONWithOptions(value string, numbersAsStrings bool) (interface{}, error) {
 	var result interface{}
 
 	// Attempt to parse JSON
-	if err := json.Unmarshal([]byte(value), &result); err != nil {
+	if numbersAsStrings {
+		dec := json.NewDecoder(strings.NewReader(value))
+		dec.UseNumber()
+		if err := dec.Decode(&result); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, fmt.Errorf("%w: invalid character after top-level value", ErrInvalidJSON)
+		}
+	} else if err := json.Unmarshal([]byte(value), &result); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
 	}
 
@@ -34,9 +54,30 @@ func TryJSON(value string) (interface{}, error) {
 		return nil, err
 	}
 
+	if numbersAsStrings {
+		result = numbersToStrings(result)
+	}
+
 	return result, nil
 }
 
+// numbersToStrings replaces json.Number values with their string literal
+func numbersToStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String()
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = numbersToStrings(val)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = numbersToStrings(val)
+		}
+	}
+	return value
+}
+
 // validateDepth recursively checks JSON nesting depth to prevent stack overflow
 func validateDepth(value interface{}, depth int) error {
 	if depth > MaxJSONDepth {
@@ -60,3 +101,31 @@ func validateDepth(value interface{}, depth int) error {
 
 	return nil
 }
+
+// ValidateArrayLength checks that no array in value, at any nesting level,
+// has more than maxLength elements. A maxLength of zero or less disables the check.
+func ValidateArrayLength(value interface{}, maxLength int) error {
+	if maxLength <= 0 {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, val := range v {
+			if err := ValidateArrayLength(val, maxLength); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if len(v) > maxLength {
+			return fmt.Errorf("%w of %d elements", ErrArrayTooLong, maxLength)
+		}
+		for _, val := range v {
+			if err := ValidateArrayLength(val, maxLength); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package converter
+
+import "strings"
+
+// RelaxJSON rewrites relaxed JSON into standard JSON by removing // line
+// comments, /* block */ comments, and trailing commas before a closing
+// bracket or brace. String contents are left untouched.
+func RelaxJSON(value string) string {
+	var out strings.Builder
+	out.Grow(len(value))
+
+	inString := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		if inString {
+			out.WriteByte(c)
+			switch c {
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					out.WriteByte(value[i])
+				}
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(value) && value[i+1] == '/':
+			// Skip to the end of the line, keeping the newline
+			for i+1 < len(value) && value[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(value) && value[i+1] == '*':
+			end := strings.Index(value[i+2:], "*/")
+			if end < 0 {
+				i = len(value)
+			} else {
+				i += end + 3
+			}
+			out.WriteByte(' ')
+		case c == ',' && closesAfterComma(value[i+1:]):
+			// Drop trailing comma
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// closesAfterComma reports whether rest begins, after whitespace and comments,
+// with a closing bracket or brace
+func closesAfterComma(rest string) bool {
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n")
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			newline := strings.IndexByte(rest, '\n')
+			if newline < 0 {
+				return false
+			}
+			rest = rest[newline+1:]
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return false
+			}
+			rest = rest[end+4:]
+		default:
+			return strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "]")
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+var (
+	// ErrSchemaValidation is returned when a JSON value does not conform to its schema
+	ErrSchemaValidation = errors.New("JSON schema validation failed")
+	// ErrInvalidSchema is returned when a schema document uses unsupported
+	// keywords or malformed keyword values
+	ErrInvalidSchema = errors.New("invalid JSON schema")
+)
+
+// supportedKeywords lists the validation keywords understood by Schema
+var supportedKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true,
+	"properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true,
+	"minLength": true, "maxLength": true, "pattern": true,
+	"minimum": true, "maximum": true, "exclusiveMinimum": true, "exclusiveMaximum": true,
+}
+
+// annotationKeywords lists keywords that carry no validation and are accepted as-is
+var annotationKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true,
+	"title": true, "description": true, "default": true, "examples": true,
+}
+
+// Schema is a JSON Schema document checked and compiled by CompileSchema.
+// Supports the commonly used subset of JSON Schema keywords: type, enum, const,
+// properties, required, additionalProperties, items, minItems, maxItems,
+// minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum and
+// exclusiveMaximum, plus annotations such as title and description.
+type Schema struct {
+	doc      map[string]interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// CompileSchema checks doc and compiles its patterns. Unsupported keywords
+// (such as oneOf, $ref or format), which would otherwise silently accept any
+// value, and invalid patterns return an error wrapping ErrInvalidSchema.
+func CompileSchema(doc map[string]interface{}) (*Schema, error) {
+	s := &Schema{doc: doc, patterns: map[string]*regexp.Regexp{}}
+	if err := s.compile(doc, "$"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// compile checks the keywords of schema and its subschemas at path
+func (s *Schema) compile(schema map[string]interface{}, path string) error {
+	keywords := make([]string, 0, len(schema))
+	for k := range schema {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		if annotationKeywords[keyword] {
+			continue
+		}
+		if !supportedKeywords[keyword] {
+			return fmt.Errorf("%w: %s: unsupported keyword %q", ErrInvalidSchema, path, keyword)
+		}
+
+		switch value := schema[keyword]; keyword {
+		case "type":
+			if err := compileType(value, path); err != nil {
+				return err
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%w: %s: pattern must be a string", ErrInvalidSchema, path)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%w: %s: invalid pattern %q: %v", ErrInvalidSchema, path, pattern, err)
+			}
+			s.patterns[pattern] = re
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%w: %s: properties must be an object", ErrInvalidSchema, path)
+			}
+			for name, propSchema := range properties {
+				sub, ok := propSchema.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%w: %s.%s: schema must be an object", ErrInvalidSchema, path, name)
+				}
+				if err := s.compile(sub, path+"."+name); err != nil {
+					return err
+				}
+			}
+		case "additionalProperties":
+			if sub, ok := value.(map[string]interface{}); ok {
+				if err := s.compile(sub, path+".*"); err != nil {
+					return err
+				}
+			} else if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%w: %s: additionalProperties must be a boolean or an object", ErrInvalidSchema, path)
+			}
+		case "items":
+			sub, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%w: %s: items must be an object", ErrInvalidSchema, path)
+			}
+			if err := s.compile(sub, path+"[]"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonTypes lists the type names accepted by the "type" keyword
+var jsonTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// compileType checks that a "type" keyword value is a known type name or a
+// non-empty list of them
+func compileType(value interface{}, path string) error {
+	var names []interface{}
+	switch t := value.(type) {
+	case string:
+		names = []interface{}{t}
+	case []interface{}:
+		if len(t) == 0 {
+			return fmt.Errorf("%w: %s: type must not be an empty list", ErrInvalidSchema, path)
+		}
+		names = t
+	default:
+		return fmt.Errorf("%w: %s: type must be a string or a list of strings", ErrInvalidSchema, path)
+	}
+	for _, name := range names {
+		typ, ok := name.(string)
+		if !ok {
+			return fmt.Errorf("%w: %s: type must be a string or a list of strings", ErrInvalidSchema, path)
+		}
+		if !slices.Contains(jsonTypes, typ) {
+			return fmt.Errorf("%w: %s: unknown type %q (supported: %v)", ErrInvalidSchema, path, typ, jsonTypes)
+		}
+	}
+	return nil
+}
+
+// Validate validates a converted value against the schema.
+// Returns an error wrapping ErrSchemaValidation describing the first violation.
+func (s *Schema) Validate(value interface{}) error {
+	return s.validateAgainst(value, s.doc, "$", false)
+}
+
+// ValidateNumbersAsStrings validates a value parsed with numbers kept as
+// strings (json_numbers_as_strings). A string holding a JSON number literal
+// then also matches the "number" and "integer" types, and numeric keywords
+// apply to the number it holds; enum and const still compare it as a string.
+func (s *Schema) ValidateNumbersAsStrings(value interface{}) error {
+	return s.validateAgainst(value, s.doc, "$", true)
+}
+
+// validateAgainst recursively validates value against schema, reporting
+// violations at path. numbersAsStrings treats number literal strings as
+// numbers too.
+func (s *Schema) validateAgainst(value interface{}, schema map[string]interface{}, path string, numbersAsStrings bool) error {
+	if err := validateType(value, schema, path, numbersAsStrings); err != nil {
+		return err
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return schemaErrorf(path, "value is not one of the allowed enum values")
+		}
+	}
+
+	if constVal, ok := schema["const"]; ok && !reflect.DeepEqual(constVal, value) {
+		return schemaErrorf(path, "value does not match const")
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return s.validateObject(v, schema, path, numbersAsStrings)
+	case []interface{}:
+		return s.validateArray(v, schema, path, numbersAsStrings)
+	case string:
+		if n, ok := numberLiteral(v, numbersAsStrings); ok {
+			if err := validateNumber(n, schema, path); err != nil {
+				return err
+			}
+		}
+		return s.validateString(v, schema, path)
+	case float64:
+		return validateNumber(v, schema, path)
+	}
+
+	return nil
+}
+
+// numberLiteral returns the number held by str when numbersAsStrings is set
+// and str is a JSON number literal
+func numberLiteral(str string, numbersAsStrings bool) (float64, bool) {
+	if !numbersAsStrings || !json.Valid([]byte(str)) {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	return n, err == nil
+}
+
+// validateType checks the "type" keyword, which compile has checked to be a
+// known type name or a list of them
+func validateType(value interface{}, schema map[string]interface{}, path string, numbersAsStrings bool) error {
+	var allowed []string
+	switch t := schema["type"].(type) {
+	case string:
+		allowed = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			allowed = append(allowed, item.(string))
+		}
+	default:
+		return nil
+	}
+
+	for _, typ := range allowed {
+		if matchesType(value, typ, numbersAsStrings) {
+			return nil
+		}
+	}
+	return schemaErrorf(path, "expected type %v, got %s", allowed, jsonTypeName(value))
+}
+
+// matchesType reports whether value is an instance of the named JSON Schema
+// type. numbersAsStrings also matches number literal strings to number types.
+func matchesType(value interface{}, typ string, numbersAsStrings bool) bool {
+	if str, ok := value.(string); ok && (typ == "number" || typ == "integer") {
+		n, ok := numberLiteral(str, numbersAsStrings)
+		return ok && (typ == "number" || n == math.Trunc(n))
+	}
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return false
+	}
+}
+
+// jsonTypeName returns the JSON type name of a parsed value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// validateObject checks object keywords: required, properties, additionalProperties
+func (s *Schema) validateObject(obj map[string]interface{}, schema map[string]interface{}, path string, numbersAsStrings bool) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			name, ok := item.(string)
+			if !ok {
+				continue
+			}
+			if _, exists := obj[name]; !exists {
+				return schemaErrorf(path, "missing required property %q", name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Iterate in sorted order so the reported violation is deterministic
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := path + "." + k
+		if propSchema, ok := properties[k].(map[string]interface{}); ok {
+			if err := s.validateAgainst(obj[k], propSchema, childPath, numbersAsStrings); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return schemaErrorf(path, "additional property %q is not allowed", k)
+			}
+		case map[string]interface{}:
+			if err := s.validateAgainst(obj[k], additional, childPath, numbersAsStrings); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateArray checks array keywords: items, minItems, maxItems
+func (s *Schema) validateArray(arr []interface{}, schema map[string]interface{}, path string, numbersAsStrings bool) error {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(arr)) < minItems {
+		return schemaErrorf(path, "array has %d items, minimum is %v", len(arr), minItems)
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > maxItems {
+		return schemaErrorf(path, "array has %d items, maximum is %v", len(arr), maxItems)
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			if err := s.validateAgainst(item, items, fmt.Sprintf("%s[%d]", path, i), numbersAsStrings); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateString checks string keywords: minLength, maxLength, pattern
+func (s *Schema) validateString(str string, schema map[string]interface{}, path string) error {
+	length := float64(utf8.RuneCountInString(str))
+	if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+		return schemaErrorf(path, "string is shorter than minimum length %v", minLength)
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+		return schemaErrorf(path, "string is longer than maximum length %v", maxLength)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if !s.patterns[pattern].MatchString(str) {
+			return schemaErrorf(path, "string does not match pattern %q", pattern)
+		}
+	}
+
+	return nil
+}
+
+// validateNumber checks numeric keywords: minimum, maximum, exclusiveMinimum, exclusiveMaximum
+func validateNumber(n float64, schema map[string]interface{}, path string) error {
+	if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+		return schemaErrorf(path, "%v is less than minimum %v", n, minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+		return schemaErrorf(path, "%v is greater than maximum %v", n, maximum)
+	}
+	if exclusiveMin, ok := schema["exclusiveMinimum"].(float64); ok && n <= exclusiveMin {
+		return schemaErrorf(path, "%v is not greater than exclusive minimum %v", n, exclusiveMin)
+	}
+	if exclusiveMax, ok := schema["exclusiveMaximum"].(float64); ok && n >= exclusiveMax {
+		return schemaErrorf(path, "%v is not less than exclusive maximum %v", n, exclusiveMax)
+	}
+	return nil
+}
+
+// schemaErrorf formats a schema violation wrapping ErrSchemaValidation
+func schemaErrorf(path, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s: %s", ErrSchemaValidation, path, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,51 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// sizeMultipliers maps supported size suffixes (lowercase) to their byte multiplier.
+// SI suffixes use powers of 1000, binary suffixes use powers of 1024.
+var sizeMultipliers = map[string]float64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// TrySize attempts to parse a size value such as "10MB", "512KB", or "2GiB".
+// Supports SI (KB, MB, GB, TB, PB) and binary (KiB, MiB, GiB, TiB, PiB) suffixes,
+// plus B for bytes, case-insensitive. Whitespace between number and suffix is allowed.
+// Returns the byte count as float64 and true if successful, 0 and false otherwise.
+// Values without a suffix are not sizes and return false.
+func TrySize(value string) (float64, bool) {
+	trimmed := strings.TrimSpace(value)
+
+	// Split into numeric part and suffix at the first letter
+	idx := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	})
+	if idx <= 0 {
+		return 0, false
+	}
+
+	multiplier, ok := sizeMultipliers[strings.ToLower(trimmed[idx:])]
+	if !ok {
+		return 0, false
+	}
+
+	num, err := strconv.ParseFloat(strings.TrimSpace(trimmed[:idx]), 64)
+	if err != nil || num < 0 {
+		return 0, false
+	}
+
+	return num * multiplier, true
+}
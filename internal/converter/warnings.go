@@ -0,0 +1,64 @@
+package converter
+
+import "strings"
+
+// Warnings returns non-fatal notes about ambiguous interpretations of value
+// under opts, such as "1" becoming a number although it could be a boolean.
+// Notes never include the value itself. Returns nil when there is nothing to
+// report or type conversion is disabled. The value goes through the same
+// preprocessing as in ConvertValueWithOptions: JSON, quoted (with
+// QuotedAsString), and multi-line (with NewlineAsArray) values are never
+// ambiguous, and StripQuotes removes surrounding quotes first.
+func Warnings(value string, opts Options) []string {
+	if !opts.EnableTypeConversion {
+		return nil
+	}
+	if opts.TrimWhitespace {
+		value = strings.TrimSpace(value)
+	}
+	trimmed := strings.TrimSpace(value)
+	if opts.EnableJSONParsing && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+		return nil
+	}
+	if opts.QuotedAsString && len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return nil
+	}
+	if opts.StripQuotes {
+		value = StripQuotes(value)
+	}
+	if opts.NewlineAsArray && strings.Contains(value, "\n") {
+		return nil
+	}
+
+	if opts.NumericBooleans && (value == "0" || value == "1") {
+		return []string{"value interpreted as boolean; could be integer"}
+	}
+
+	if num, ok := TryNumeric(value); ok {
+		var warnings []string
+		if value == "0" || value == "1" {
+			warnings = append(warnings, "value interpreted as integer; could be boolean")
+		}
+		if hasLeadingZeros(value) {
+			warnings = append(warnings, "value interpreted as "+NumberType(value, num)+"; leading zeros are dropped, could be string")
+		}
+		return warnings
+	}
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "no":
+		return []string{"value interpreted as boolean; could be string"}
+	case "t", "f":
+		if opts.RecognizeTF {
+			return []string{"value interpreted as boolean; could be string"}
+		}
+	}
+	return nil
+}
+
+// hasLeadingZeros reports whether a numeric literal has a redundant leading
+// zero in its integer part, e.g. "007" or "-01.5"
+func hasLeadingZeros(literal string) bool {
+	digits := strings.TrimLeft(literal, "+-")
+	return len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9'
+}
@@ -3,8 +3,10 @@ package fetcher
 
 import (
 	"errors"
-	"os"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -19,34 +21,170 @@ const MaxValueSize = 1 * 1024 * 1024
 
 // Fetcher retrieves environment variables with caching support.
 type Fetcher struct {
-	cache sync.Map
+	source   atomic.Pointer[EnvSource]
+	cache    sync.Map
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	negative atomic.Bool
+	// caseInsensitive enables a case-insensitive scan on lookup misses;
+	// actualNames caches the resolved actual names by requested name.
+	caseInsensitive atomic.Bool
+	actualNames     sync.Map
 }
 
-// New creates a new Fetcher instance.
+// notFound marks a cached negative (not found) lookup
+type notFound struct{}
+
+// New creates a new Fetcher instance reading the process environment.
 func New() *Fetcher {
-	return &Fetcher{}
+	return NewWithSource(ProcessEnv())
+}
+
+// NewWithSource creates a Fetcher reading from the given source.
+func NewWithSource(source EnvSource) *Fetcher {
+	f := &Fetcher{}
+	f.SetSource(source)
+	return f
+}
+
+// SetSource replaces the source the fetcher reads from. It is safe to call
+// concurrently with lookups. Cached values are kept, so callers switching
+// sources should Clear the cache as well.
+func (f *Fetcher) SetSource(source EnvSource) {
+	f.source.Store(&source)
+}
+
+// loadSource returns the current source
+func (f *Fetcher) loadSource() EnvSource {
+	return *f.source.Load()
 }
 
 // Fetch retrieves an environment variable by name, using cache if available.
+// With negative caching enabled, not-found results are cached as well.
 func (f *Fetcher) Fetch(varName string) (string, error) {
 	if cached, ok := f.cache.Load(varName); ok {
-		return cached.(string), nil
+		if _, missing := cached.(notFound); !missing {
+			f.hits.Add(1)
+			return cached.(string), nil
+		}
+		// Negative entries only count while negative caching is enabled
+		if f.negative.Load() {
+			f.hits.Add(1)
+			return "", ErrNotFound
+		}
+	}
+	f.misses.Add(1)
+	value, err := f.FetchLive(varName)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) && f.negative.Load() {
+			f.cache.Store(varName, notFound{})
+		}
+		return "", err
+	}
+	f.cache.Store(varName, value)
+	return value, nil
+}
+
+// SetCacheNegative enables or disables caching of not-found lookups in Fetch.
+// Cached negative results persist until Clear, which the provider calls on
+// every Init and on Shutdown.
+func (f *Fetcher) SetCacheNegative(enabled bool) {
+	f.negative.Store(enabled)
+}
+
+// FetchLive reads an environment variable directly from the source,
+// ignoring and not populating the cache.
+func (f *Fetcher) FetchLive(varName string) (string, error) {
+	value, _, err := f.FetchSource(varName)
+	return value, err
+}
+
+// FetchSource reads an environment variable directly from the source and
+// reports the name of the source that satisfied the lookup. For a
+// ChainedEnvSource this is the name of the winning member source.
+func (f *Fetcher) FetchSource(varName string) (value, source string, err error) {
+	value, source, exists := f.lookup(varName)
+	if !exists && f.caseInsensitive.Load() {
+		if actual, found := f.actualName(varName); found {
+			value, source, exists = f.lookup(actual)
+		}
 	}
-	value, exists := os.LookupEnv(varName)
 	if !exists {
-		return "", ErrNotFound
+		return "", "", ErrNotFound
 	}
 	if len(value) > MaxValueSize {
-		return "", ErrValueTooLarge
+		return "", "", ErrValueTooLarge
 	}
-	f.cache.Store(varName, value)
-	return value, nil
+	return value, source, nil
 }
 
-// Clear removes all cached environment variable values.
+// lookup reads varName from the source, reporting the satisfying source name
+func (f *Fetcher) lookup(varName string) (value, source string, exists bool) {
+	src := f.loadSource()
+	if chain, ok := src.(*ChainedEnvSource); ok {
+		return chain.LookupSource(varName)
+	}
+	value, exists = src.Lookup(varName)
+	return value, src.Name(), exists
+}
+
+// actualName returns the name of a variable in the source that equals
+// varName case-insensitively. Resolved names are cached; a cached name that
+// no longer exists is re-resolved.
+func (f *Fetcher) actualName(varName string) (string, bool) {
+	if cached, ok := f.actualNames.Load(varName); ok {
+		if _, _, exists := f.lookup(cached.(string)); exists {
+			return cached.(string), true
+		}
+		f.actualNames.Delete(varName)
+	}
+	for _, name := range f.loadSource().Names() {
+		if strings.EqualFold(name, varName) {
+			f.actualNames.Store(varName, name)
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// SetCaseInsensitive enables or disables case-insensitive lookups. When
+// enabled, a lookup miss scans the source for a name differing only in case.
+func (f *Fetcher) SetCaseInsensitive(enabled bool) {
+	f.caseInsensitive.Store(enabled)
+}
+
+// Stats returns the current number of cached entries and the cumulative
+// number of cache hits and misses in Fetch.
+func (f *Fetcher) Stats() (entries int, hits, misses uint64) {
+	f.cache.Range(func(_, _ interface{}) bool {
+		entries++
+		return true
+	})
+	return entries, f.hits.Load(), f.misses.Load()
+}
+
+// Names returns the sorted names of all environment variables starting with prefix.
+// Names are read live from the source and are not cached.
+func (f *Fetcher) Names(prefix string) []string {
+	var names []string
+	for _, name := range f.loadSource().Names() {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Clear removes all cached environment variable values and resolved
+// case-insensitive names.
 func (f *Fetcher) Clear() {
 	f.cache.Range(func(key, _ interface{}) bool {
 		f.cache.Delete(key)
 		return true
 	})
+	f.actualNames.Range(func(key, _ interface{}) bool {
+		f.actualNames.Delete(key)
+		return true
+	})
 }
@@ -1,6 +1,7 @@
 package fetcher
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"testing"
@@ -176,3 +177,79 @@ func TestFetcherCaching(t *testing.T) {
 		t.Errorf("second fetch should return cached value %q, got %q", "initial_value", val2)
 	}
 }
+
+func TestFetcherStats(t *testing.T) {
+	t.Setenv("TEST_STATS_A", "a")
+	t.Setenv("TEST_STATS_B", "b")
+
+	f := New()
+	assertStats := func(wantEntries int, wantHits, wantMisses uint64) {
+		t.Helper()
+		entries, hits, misses := f.Stats()
+		if entries != wantEntries || hits != wantHits || misses != wantMisses {
+			t.Errorf("Stats() = (%d, %d, %d), want (%d, %d, %d)", entries, hits, misses, wantEntries, wantHits, wantMisses)
+		}
+	}
+
+	assertStats(0, 0, 0)
+	_, _ = f.Fetch("TEST_STATS_A") // miss
+	_, _ = f.Fetch("TEST_STATS_A") // hit
+	_, _ = f.Fetch("TEST_STATS_B") // miss
+	_, _ = f.Fetch("TEST_STATS_MISSING")
+	assertStats(2, 1, 3)
+
+	// FetchLive bypasses the cache and its counters
+	_, _ = f.FetchLive("TEST_STATS_A")
+	assertStats(2, 1, 3)
+
+	// Clear drops entries but keeps cumulative counters
+	f.Clear()
+	assertStats(0, 1, 3)
+}
+
+func TestFetcherCacheNegative(t *testing.T) {
+	const name = "TEST_CACHE_NEGATIVE_VAR"
+	if err := os.Unsetenv(name); err != nil {
+		t.Fatalf("failed to unset %s: %v", name, err)
+	}
+
+	f := New()
+	f.SetCacheNegative(true)
+
+	if _, err := f.Fetch(name); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Fetch() error = %v, want ErrNotFound", err)
+	}
+
+	// The negative result is served from cache even after the variable appears
+	t.Setenv(name, "now-set")
+	if _, err := f.Fetch(name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Fetch() error = %v, want cached ErrNotFound", err)
+	}
+	if entries, hits, misses := f.Stats(); entries != 1 || hits != 1 || misses != 1 {
+		t.Errorf("Stats() = (%d, %d, %d), want (1, 1, 1)", entries, hits, misses)
+	}
+
+	// Invalidation makes the new value visible
+	f.Clear()
+	if value, err := f.Fetch(name); err != nil || value != "now-set" {
+		t.Errorf("Fetch() after Clear = (%q, %v), want (%q, nil)", value, err, "now-set")
+	}
+}
+
+func TestFetcherNegativeCacheDisabled(t *testing.T) {
+	const name = "TEST_CACHE_NEGATIVE_DISABLED_VAR"
+	if err := os.Unsetenv(name); err != nil {
+		t.Fatalf("failed to unset %s: %v", name, err)
+	}
+
+	f := New()
+	f.SetCacheNegative(true)
+	_, _ = f.Fetch(name)
+
+	// Disabling negative caching ignores previously cached negative entries
+	f.SetCacheNegative(false)
+	t.Setenv(name, "visible")
+	if value, err := f.Fetch(name); err != nil || value != "visible" {
+		t.Errorf("Fetch() = (%q, %v), want (%q, nil)", value, err, "visible")
+	}
+}
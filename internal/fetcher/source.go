@@ -0,0 +1,135 @@
+package fetcher
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvSource is a source of environment variables.
+type EnvSource interface {
+	// Name identifies the source when reporting where a value came from.
+	Name() string
+	// Lookup returns the value of the named variable and whether it exists.
+	Lookup(name string) (string, bool)
+	// Names returns the names of all variables in the source.
+	Names() []string
+}
+
+// ProcessEnvSourceName is the name reported by ProcessEnv.
+const ProcessEnvSourceName = "process"
+
+// processEnv reads from the process environment.
+type processEnv struct{}
+
+// ProcessEnv returns an EnvSource backed by the process environment.
+func ProcessEnv() EnvSource {
+	return processEnv{}
+}
+
+func (processEnv) Name() string { return ProcessEnvSourceName }
+
+func (processEnv) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (processEnv) Names() []string {
+	var names []string
+	for _, entry := range os.Environ() {
+		name, _, found := strings.Cut(entry, "=")
+		if !found || name == "" {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// MapSource is an EnvSource backed by an in-memory map, e.g. injected values
+// or the parsed contents of an env file.
+type MapSource struct {
+	name   string
+	values map[string]string
+}
+
+// NewMapSource creates a named EnvSource over a copy of values.
+func NewMapSource(name string, values map[string]string) *MapSource {
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return &MapSource{name: name, values: copied}
+}
+
+// Name returns the source name.
+func (m *MapSource) Name() string { return m.name }
+
+// Lookup returns the value of the named variable.
+func (m *MapSource) Lookup(name string) (string, bool) {
+	value, ok := m.values[name]
+	return value, ok
+}
+
+// Names returns the names of all variables in the map.
+func (m *MapSource) Names() []string {
+	names := make([]string, 0, len(m.values))
+	for name := range m.values {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ChainedEnvSource consults sources in priority order; the first source
+// containing a variable wins.
+type ChainedEnvSource struct {
+	sources []EnvSource
+}
+
+// NewChainedEnvSource creates a chain whose sources are consulted in the
+// given order (highest priority first).
+func NewChainedEnvSource(sources ...EnvSource) *ChainedEnvSource {
+	return &ChainedEnvSource{sources: sources}
+}
+
+// Name returns the names of the chained sources joined by ">".
+func (c *ChainedEnvSource) Name() string {
+	names := make([]string, len(c.sources))
+	for i, s := range c.sources {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, ">")
+}
+
+// Lookup returns the value from the highest-priority source containing name.
+func (c *ChainedEnvSource) Lookup(name string) (string, bool) {
+	value, _, ok := c.LookupSource(name)
+	return value, ok
+}
+
+// LookupSource returns the value and the name of the source that satisfied
+// the lookup.
+func (c *ChainedEnvSource) LookupSource(name string) (value, source string, ok bool) {
+	for _, s := range c.sources {
+		if value, ok := s.Lookup(name); ok {
+			return value, s.Name(), true
+		}
+	}
+	return "", "", false
+}
+
+// Names returns the sorted, de-duplicated names across all sources.
+func (c *ChainedEnvSource) Names() []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, s := range c.sources {
+		for _, name := range s.Names() {
+			if _, dup := seen[name]; dup {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,95 @@
+package fetcher
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChainedEnvSourcePriority(t *testing.T) {
+	injected := NewMapSource("injected", map[string]string{
+		"SHARED":        "from-injected",
+		"INJECTED_ONLY": "i",
+	})
+	file := NewMapSource("file", map[string]string{
+		"SHARED":    "from-file",
+		"FILE_ONLY": "f",
+	})
+	f := NewWithSource(NewChainedEnvSource(injected, file))
+
+	tests := []struct {
+		name       string
+		wantValue  string
+		wantSource string
+	}{
+		{"SHARED", "from-injected", "injected"},
+		{"INJECTED_ONLY", "i", "injected"},
+		{"FILE_ONLY", "f", "file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, source, err := f.FetchSource(tt.name)
+			if err != nil {
+				t.Fatalf("FetchSource() error = %v", err)
+			}
+			if value != tt.wantValue || source != tt.wantSource {
+				t.Errorf("FetchSource() = (%q, %q), want (%q, %q)", value, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+
+	if _, _, err := f.FetchSource("MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("FetchSource(MISSING) error = %v, want ErrNotFound", err)
+	}
+
+	want := []string{"FILE_ONLY", "INJECTED_ONLY", "SHARED"}
+	if got := f.Names(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestChainedEnvSourceWithProcessEnv(t *testing.T) {
+	t.Setenv("TEST_CHAIN_PROCESS", "from-process")
+	fallback := NewMapSource("defaults", map[string]string{
+		"TEST_CHAIN_PROCESS":  "from-defaults",
+		"TEST_CHAIN_FALLBACK": "fallback",
+	})
+	f := NewWithSource(NewChainedEnvSource(ProcessEnv(), fallback))
+
+	if value, source, _ := f.FetchSource("TEST_CHAIN_PROCESS"); value != "from-process" || source != ProcessEnvSourceName {
+		t.Errorf("FetchSource() = (%q, %q), want process value", value, source)
+	}
+	if value, source, _ := f.FetchSource("TEST_CHAIN_FALLBACK"); value != "fallback" || source != "defaults" {
+		t.Errorf("FetchSource() = (%q, %q), want defaults value", value, source)
+	}
+}
+
+func TestSetSourceDuringLookups(t *testing.T) {
+	first := NewMapSource("first", map[string]string{"TEST_SWAP": "one"})
+	second := NewMapSource("second", map[string]string{"TEST_SWAP": "two"})
+	f := NewWithSource(first)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if _, err := f.FetchLive("TEST_SWAP"); err != nil {
+				t.Errorf("FetchLive() error = %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		if i%2 == 0 {
+			f.SetSource(second)
+		} else {
+			f.SetSource(first)
+		}
+	}
+	<-done
+
+	f.SetSource(second)
+	if _, source, _ := f.FetchSource("TEST_SWAP"); source != "second" {
+		t.Errorf("FetchSource() source = %q, want %q", source, "second")
+	}
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// encodeBinary represents a value that is not valid UTF-8 according to the
+// binary_encoding option. Returned errors are gRPC status errors.
+func (p *Provider) encodeBinary(varName, value string) (interface{}, string, error) {
+	switch p.config.BinaryEncoding {
+	case "base64":
+		return base64.StdEncoding.EncodeToString([]byte(value)), "binary", nil
+	case "hex":
+		return hex.EncodeToString([]byte(value)), "binary", nil
+	default:
+		p.logger.Error("environment variable is not valid UTF-8: %s", p.logName(varName))
+		return nil, "", status.Errorf(codes.InvalidArgument, "environment variable %s is not valid UTF-8 (set binary_encoding to base64 or hex)", varName)
+	}
+}
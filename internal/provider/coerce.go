@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
+)
+
+// CoerceToMetadataKey is the gRPC metadata key a client sets to "number",
+// "boolean", or "string" to have a Fetch convert the trimmed value to that
+// type instead of relying on automatic type detection.
+const CoerceToMetadataKey = "x-nomos-coerce-to"
+
+// coerceTarget returns the coercion target requested in the metadata, or ""
+// when none is set. Returned errors are gRPC status errors.
+func coerceTarget(ctx context.Context) (string, error) {
+	values := metadata.ValueFromIncomingContext(ctx, CoerceToMetadataKey)
+	if len(values) == 0 {
+		return "", nil
+	}
+	target := strings.ToLower(strings.TrimSpace(values[len(values)-1]))
+	switch target {
+	case "number", "boolean", "string":
+		return target, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "invalid %s %q: must be number, boolean, or string", CoerceToMetadataKey, target)
+	}
+}
+
+// coerceValue converts the trimmed value of varName to target. Errors never
+// include the value. Returned errors are gRPC status errors.
+func (p *Provider) coerceValue(varName, value, target string) (interface{}, string, error) {
+	trimmed := strings.TrimSpace(value)
+	switch target {
+	case "number":
+		if num, ok := converter.TryNumeric(trimmed); ok {
+			return num, converter.NumberType(trimmed, num), nil
+		}
+	case "boolean":
+		b, ok := converter.TryBoolean(trimmed)
+		if !ok && p.config.RecognizeTF {
+			b, ok = converter.TryTFBoolean(trimmed)
+		}
+		if ok {
+			result, typeStr := p.formatBoolean(b)
+			return result, typeStr, nil
+		}
+	default:
+		return trimmed, "string", nil
+	}
+	p.logger.Error("cannot coerce %s to %s", p.logName(varName), target)
+	return nil, "", status.Errorf(codes.InvalidArgument, "cannot coerce %s to %s", varName, target)
+}
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/config"
+)
+
+// checkLength validates the length in characters of the value of varName
+// against its variable_constraints entry. Errors name the variable and the
+// violated bound but never include the value.
+func checkLength(varName, value string, constraint config.LengthConstraint) error {
+	length := utf8.RuneCountInString(value)
+	if constraint.MinLength > 0 && length < constraint.MinLength {
+		return fmt.Errorf("environment variable %s is shorter than min_length %d", varName, constraint.MinLength)
+	}
+	if constraint.MaxLength > 0 && length > constraint.MaxLength {
+		return fmt.Errorf("environment variable %s is longer than max_length %d", varName, constraint.MaxLength)
+	}
+	return nil
+}
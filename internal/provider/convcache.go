@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"container/list"
+	"sync"
+)
+
+// conversionCache is a bounded LRU cache of converted values keyed by the raw
+// string. Cached maps and slices are shared between fetches and must be
+// treated as read-only.
+type conversionCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+// conversionEntry is a cached conversion result
+type conversionEntry struct {
+	raw     string
+	value   interface{}
+	typeStr string
+}
+
+// newConversionCache creates a cache holding at most size entries
+func newConversionCache(size int) *conversionCache {
+	return &conversionCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached conversion of raw, marking it as recently used
+func (c *conversionCache) get(raw string) (interface{}, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[raw]
+	if !ok {
+		return nil, "", false
+	}
+	c.order.MoveToFront(elem)
+	entry := elem.Value.(*conversionEntry)
+	return entry.value, entry.typeStr, true
+}
+
+// put stores the conversion of raw, evicting the least recently used entry when full
+func (c *conversionCache) put(raw string, value interface{}, typeStr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[raw]; ok {
+		c.order.MoveToFront(elem)
+		entry := elem.Value.(*conversionEntry)
+		entry.value, entry.typeStr = value, typeStr
+		return
+	}
+
+	c.entries[raw] = c.order.PushFront(&conversionEntry{raw: raw, value: value, typeStr: typeStr})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*conversionEntry).raw)
+	}
+}
+
+// clear removes all entries
+func (c *conversionCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+}
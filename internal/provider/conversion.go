@@ -3,16 +3,130 @@ package provider
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
 )
 
-// convertValue applies type conversion to a string value based on provider configuration
-func (p *Provider) convertValue(value string) (interface{}, error) {
+// DisableConversionEnvVar names the process environment variable that, when
+// truthy, forces Fetch to return raw string values regardless of the
+// configuration. It is read on every Fetch, so it takes effect without
+// re-initializing the provider; intended as a diagnostic escape hatch.
+const DisableConversionEnvVar = "NOMOS_DISABLE_CONVERSION"
+
+// conversionDisabled reports whether NOMOS_DISABLE_CONVERSION is truthy
+func conversionDisabled() bool {
+	disabled, err := strconv.ParseBool(os.Getenv(DisableConversionEnvVar))
+	return err == nil && disabled
+}
+
+// convertValue applies type conversion to the value of varName based on provider
+// configuration, including any prefix_conversion_overrides matching varName.
+// Returns the converted value and its detected type string.
+// Results are served from the conversion cache when conversion_cache_size is set.
+func (p *Provider) convertValue(varName, value string) (interface{}, string, error) {
+	opts, variant := p.converterOptions(varName)
+
+	// Results depend on the options, so per-variable variants get their own
+	// keys, and on the custom converter registry, which may change at any time
+	var cacheKey string
+	cache := p.convCache
+	if cache != nil {
+		cacheKey = variant + "\x00" + value
+		if len(opts.CustomConverters) > 0 {
+			cacheKey = strconv.FormatUint(converter.RegistryGeneration(), 10) + "\x00" + cacheKey
+		}
+		if converted, typeStr, ok := cache.get(cacheKey); ok {
+			return converted, typeStr, nil
+		}
+	}
+
 	// Call the converter package which handles automatic type detection
 	// Pass the config flags to control conversion behavior
-	converted, _, err := converter.ConvertValue(value, p.config.EnableTypeConversion, p.config.EnableJSONParsing)
-	return converted, err
+	converted, typeStr, err := converter.ConvertValueWithOptions(value, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cache != nil {
+		cache.put(cacheKey, converted, typeStr)
+	}
+	return converted, typeStr, nil
+}
+
+// converterOptions builds converter options for varName from the provider
+// configuration. It also returns a key identifying the per-variable variant of
+// the options: the prefix_conversion_overrides prefix that was applied (the
+// longest matching one), marked when bool_numeric_variables lists varName.
+// The key is "" when no per-variable setting applies.
+func (p *Provider) converterOptions(varName string) (converter.Options, string) {
+	opts := converter.Options{
+		EnableTypeConversion:  p.config.EnableTypeConversion,
+		EnableJSONParsing:     p.config.EnableJSONParsing,
+		NullAsNull:            p.config.NullAsNull,
+		EnableSizeParsing:     p.config.EnableSizeParsing,
+		EnableDurationParsing: p.config.EnableDurationParsing,
+		StripQuotes:           p.config.StripQuotes,
+		QuotedAsString:        p.config.QuotedAsString,
+		JSONNumbersAsStrings:  p.config.JSONNumbersAsStrings,
+		NewlineAsArray:        p.config.NewlineAsArray,
+		TrimWhitespace:        p.config.TrimWhitespace,
+		EnableRelaxedJSON:     p.config.EnableRelaxedJSON,
+		StrictConversion:      p.config.StrictConversion,
+		RecognizeTF:           p.config.RecognizeTF,
+		MaxArrayLength:        p.config.MaxArrayLength,
+		CustomConverters:      p.config.CustomConverters,
+	}
+
+	matched := ""
+	for prefix := range p.config.PrefixConversionOverrides {
+		if strings.HasPrefix(varName, prefix) && len(prefix) > len(matched) {
+			matched = prefix
+		}
+	}
+	variant := matched
+
+	if matched != "" {
+		override := p.config.PrefixConversionOverrides[matched]
+		applyOverride(&opts.EnableTypeConversion, override.EnableTypeConversion)
+		applyOverride(&opts.EnableJSONParsing, override.EnableJSONParsing)
+		applyOverride(&opts.NullAsNull, override.NullAsNull)
+		applyOverride(&opts.EnableSizeParsing, override.EnableSizeParsing)
+		applyOverride(&opts.StripQuotes, override.StripQuotes)
+	}
+
+	for _, name := range p.config.BoolNumericVariables {
+		if name == varName {
+			opts.NumericBooleans = true
+			variant += "\x01"
+			break
+		}
+	}
+	return opts, variant
+}
+
+// applyOverride sets *flag to *override when override is set
+func applyOverride(flag, override *bool) {
+	if override != nil {
+		*flag = *override
+	}
+}
+
+// formatBoolean renders a detected boolean according to the boolean_output option
+func (p *Provider) formatBoolean(value bool) (interface{}, string) {
+	switch p.config.BooleanOutput {
+	case "numeric":
+		if value {
+			return float64(1), "integer"
+		}
+		return float64(0), "integer"
+	case "string":
+		return strconv.FormatBool(value), "string"
+	default:
+		return value, "boolean"
+	}
 }
 
 // toProtoValue converts a Go value to a protobuf Value
@@ -0,0 +1,16 @@
+package provider
+
+// lookupReference resolves a ${VAR} reference or {{.VAR}} template field
+// through the fetcher. References rejected by the filter_only prefix filter
+// are treated as unresolved so expansion and templates cannot be used to
+// read filtered variables. Values matching null_tokens are unresolved too.
+func (p *Provider) lookupReference(name string) (string, bool) {
+	if !p.allowedByPrefix(name) {
+		return "", false
+	}
+	value, err := p.fetcher.Fetch(name)
+	if err != nil || p.isNullToken(value) {
+		return "", false
+	}
+	return value, true
+}
@@ -3,25 +3,52 @@ package provider
 import (
 	"context"
 	"errors"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/fetcher"
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
 	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
 )
 
 // Fetch retrieves configuration data at the specified path
-func (p *Provider) Fetch(_ context.Context, req *pb.FetchRequest) (*pb.FetchResponse, error) {
+func (p *Provider) Fetch(ctx context.Context, req *pb.FetchRequest) (*pb.FetchResponse, error) {
+	p.touch()
+	// Hold the read lock so a concurrent Init cannot swap the configuration,
+	// fetcher or resolver mid-fetch
+	p.mu.RLock()
+	ctx, cancel := p.withRequestTimeout(ctx)
+	resp, err := p.fetch(ctx, req)
+	cancel()
+	p.mu.RUnlock()
+	p.metrics.recordFetch(err)
+	return resp, err
+}
+
+// fetch implements Fetch. Returned errors are gRPC status errors.
+func (p *Provider) fetch(ctx context.Context, req *pb.FetchRequest) (*pb.FetchResponse, error) {
 	// Check if initialized
 	if p.GetState() != StateReady {
 		p.logger.Error("fetch called before initialization")
 		return nil, status.Error(codes.FailedPrecondition, "provider not initialized")
 	}
 
+	// Bound the number of in-flight fetches
+	release, err := p.acquireFetchSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Validate path
 	if len(req.Path) == 0 {
 		p.logger.Error("fetch called with empty path")
@@ -29,66 +56,339 @@ func (p *Provider) Fetch(_ context.Context, req *pb.FetchRequest) (*pb.FetchResp
 	}
 
 	for i, segment := range req.Path {
-		if strings.TrimSpace(segment) == "" {
+		if strings.TrimSpace(segment) == "" && !p.config.DropEmptySegments {
 			p.logger.Error("fetch called with empty path segment at index %d", i)
 			return nil, status.Errorf(codes.InvalidArgument, "path[%d] cannot be empty string", i)
 		}
 	}
 
-	// Determine the variable name to fetch
-	var varName string
-	var err error
+	// Type requested by the client, overriding automatic detection
+	target, err := coerceTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(req.Path) == 1 {
-		// Single-segment path: direct environment variable access
-		varName = req.Path[0]
-		p.logger.Debug("fetching environment variable (direct): %s", varName)
-	} else {
-		// Multi-segment path: transform using resolver
-		varName, err = p.resolver.Transform(req.Path)
-		if err != nil {
-			p.logger.Error("path transformation failed for %v: %v", req.Path, err)
-			return nil, status.Errorf(codes.InvalidArgument, "path transformation failed: %v", err)
-		}
-		p.logger.Debug("fetching environment variable (transformed): %s from path %v", varName, req.Path)
+	// Determine the variable name to fetch
+	varName, err := p.resolveVarName(req.Path)
+	if err != nil {
+		return nil, err
 	}
 
 	// In filter_only mode, check if the variable passes the prefix filter
 	// This prevents access to variables that don't have the required prefix
-	if p.config.PrefixMode == "filter_only" && p.config.Prefix != "" {
-		if !resolver.FilterByPrefix(varName, p.config.Prefix) {
-			p.logger.Warn("environment variable does not match prefix filter: %s (prefix: %s)", varName, p.config.Prefix)
-			return nil, status.Errorf(codes.NotFound, "environment variable not found: %s", varName)
+	if !p.allowedByPrefix(varName) {
+		p.logger.Warn("environment variable does not match prefix filter: %s (prefix: %s)", p.logName(varName), p.config.Prefix)
+		return nil, status.Errorf(codes.NotFound, "environment variable not found: %s", varName)
+	}
+
+	// Fetch from environment, reading the live value when requested by the client
+	fetch := p.fetcher.Fetch
+	if bypassCache(ctx) {
+		fetch = p.fetcher.FetchLive
+		p.logger.Debug("bypassing cache for %s", p.logName(varName))
+	}
+	value, err := p.readValue(fetch, varName)
+	if errors.Is(err, fetcher.ErrNotFound) {
+		// Fall back to grouping indexed variables (e.g. SERVER_1_HOST) under the path
+		if p.config.GroupIndexed {
+			group, found, groupErr := p.fetchIndexedGroup(ctx, req.Path)
+			if groupErr != nil {
+				return nil, groupErr
+			}
+			if found {
+				p.logger.Debug("successfully fetched indexed group %s", p.logName(varName))
+				return p.buildResponse(varName, false, group, "object", nil)
+			}
+		}
+		// A variable validated as required at Init has since been removed
+		if p.config.RequiredMissingAsPrecondition && p.isRequired(varName) {
+			p.logger.Error("required environment variable no longer set: %s", p.logName(varName))
+			return nil, status.Errorf(codes.FailedPrecondition, "required environment variable %s was present at Init but is no longer set", varName)
 		}
+		p.logger.Warn("environment variable not found: %s", p.logName(varName))
+		return nil, status.Errorf(codes.NotFound, "environment variable not found: %s", varName)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch from environment
-	value, err := p.fetcher.Fetch(varName)
+	convertedValue, typeStr, warnings, err := p.processValue(ctx, varName, value, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit trail of successful fetches (never including the value)
+	if p.config.LogFetchSuccess {
+		p.logger.Info("successfully fetched %s", p.logName(varName))
+	} else {
+		p.logger.Debug("successfully fetched %s", p.logName(varName))
+	}
+
+	return p.buildResponse(varName, true, convertedValue, typeStr, warnings)
+}
+
+// readValue fetches varName with fetch and applies the per-variable checks
+// (null_tokens, variable_max_sizes, reject_control_chars, enforced
+// variable_constraints, and deprecated_variables warnings). A missing
+// variable or null token is reported as fetcher.ErrNotFound; other returned
+// errors are gRPC status errors.
+func (p *Provider) readValue(fetch func(string) (string, error), varName string) (string, error) {
+	value, err := fetch(varName)
+	if err == nil && p.isNullToken(value) {
+		p.logger.Debug("value of %s is a null token, treating it as missing", p.logName(varName))
+		err = fetcher.ErrNotFound
+	}
 	if err != nil {
 		if errors.Is(err, fetcher.ErrNotFound) {
-			p.logger.Warn("environment variable not found: %s", varName)
-			return nil, status.Errorf(codes.NotFound, "environment variable not found: %s", varName)
+			return "", err
 		}
 		if errors.Is(err, fetcher.ErrValueTooLarge) {
-			p.logger.Error("environment variable value too large: %s", varName)
-			return nil, status.Errorf(codes.InvalidArgument, "environment variable value exceeds maximum size of %d bytes", fetcher.MaxValueSize)
+			p.logger.Error("environment variable value too large: %s", p.logName(varName))
+			return "", status.Errorf(codes.InvalidArgument, "environment variable value exceeds maximum size of %d bytes", fetcher.MaxValueSize)
+		}
+		p.logger.Error("fetch failed for %s: %v", p.logName(varName), err)
+		return "", status.Errorf(codes.Internal, "fetch failed: %v", err)
+	}
+
+	// Enforce a tighter per-variable size limit when configured
+	if limit, ok := p.config.VariableMaxSizes[varName]; ok && len(value) > limit {
+		p.logger.Error("environment variable value too large: %s (%d bytes, limit %d)", p.logName(varName), len(value), limit)
+		return "", status.Errorf(codes.InvalidArgument, "environment variable %s exceeds its maximum size of %d bytes", varName, limit)
+	}
+
+	// Reject values with control characters, which usually indicate corruption
+	if p.config.RejectControlChars && hasControlChars(value) {
+		p.logger.Error("environment variable contains control characters: %s", p.logName(varName))
+		return "", status.Errorf(codes.InvalidArgument, "environment variable %s contains control characters", varName)
+	}
+
+	// Enforce variable_constraints length bounds when requested
+	if constraint, ok := p.config.VariableConstraints[varName]; ok && p.config.EnforceConstraintsOnFetch {
+		if err := checkLength(varName, value, constraint); err != nil {
+			p.logger.Error("length constraint violated: %s", p.logName(varName))
+			return "", status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	p.warnIfDeprecated(varName)
+	return value, nil
+}
+
+// resolveVarName determines the environment variable name for a non-empty
+// path. Returned errors are gRPC status errors.
+func (p *Provider) resolveVarName(path []string) (string, error) {
+	varName, how, err := resolvePath(p.resolver, path)
+	if err != nil {
+		p.logger.Error("path resolution failed for %v: %v", path, err)
+		return "", err
+	}
+	if how != "transformed" || p.isSecret(varName) {
+		p.logger.Debug("fetching environment variable (%s): %s", how, p.logName(varName))
+	} else {
+		p.logger.Debug("fetching environment variable (transformed): %s from path %v", varName, path)
+	}
+	return varName, nil
+}
+
+// resolvePath resolves path to a variable name with r, reporting how it was
+// resolved: "raw", "direct" (single segment), or "transformed". It neither
+// logs nor reads provider state, so Init can resolve paths before committing
+// a new configuration. Returned errors are gRPC status errors.
+func resolvePath(r *resolver.Resolver, path []string) (varName, how string, err error) {
+	if rawName, isRaw := rawVariableName(path); isRaw {
+		// Raw path: literal variable name, bypassing all transformation
+		if rawName == "" {
+			return "", "", status.Error(codes.InvalidArgument, "raw variable name cannot be empty")
+		}
+		return rawName, "raw", nil
+	}
+
+	if normalized := r.NormalizePath(path); len(normalized) == 1 {
+		// Single-segment path: direct environment variable access
+		return r.MapNameChars(normalized[0]), "direct", nil
+	}
+
+	// Multi-segment path: transform using resolver
+	varName, err = r.Transform(path)
+	if err != nil {
+		return "", "", status.Errorf(codes.InvalidArgument, "path transformation failed: %v", err)
+	}
+	return varName, "transformed", nil
+}
+
+// indirectionDepth returns the maximum depth of indirect resolution, such as
+// nested ${VAR} references: max_indirection_depth, or the default when unset.
+func (p *Provider) indirectionDepth() int {
+	if p.config.MaxIndirectionDepth > 0 {
+		return p.config.MaxIndirectionDepth
+	}
+	return resolver.DefaultMaxExpansionDepth
+}
+
+// isRequired reports whether varName is listed in required_variables.
+func (p *Provider) isRequired(varName string) bool {
+	for _, name := range p.config.RequiredVariables {
+		if name == varName {
+			return true
+		}
+	}
+	return false
+}
+
+// hasControlChars reports whether value contains an ASCII control character
+// other than tab, newline, or carriage return.
+func hasControlChars(value string) bool {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c < 0x20 && c != '\t' && c != '\n' && c != '\r') || c == 0x7f {
+			return true
 		}
-		p.logger.Error("fetch failed for %s: %v", varName, err)
-		return nil, status.Errorf(codes.Internal, "fetch failed: %v", err)
 	}
+	return false
+}
 
-	// Apply type conversion if enabled
-	var convertedValue interface{} = value
-	if p.config.EnableTypeConversion || p.config.EnableJSONParsing {
-		var converted interface{}
-		converted, err = p.convertValue(value)
+// isNullToken reports whether value matches one of the null_tokens
+// (case-insensitive, ignoring surrounding whitespace).
+func (p *Provider) isNullToken(value string) bool {
+	trimmed := strings.TrimSpace(value)
+	for _, token := range p.config.NullTokens {
+		if strings.EqualFold(trimmed, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskedName replaces the names of secret_variables in log output
+const maskedName = "[secret]"
+
+// isSecret reports whether varName is listed in secret_variables.
+func (p *Provider) isSecret(varName string) bool {
+	return slices.Contains(p.config.SecretVariables, varName)
+}
+
+// logName returns varName for log output, masked when it is a secret.
+func (p *Provider) logName(varName string) string {
+	return maskName(p.config.SecretVariables, varName)
+}
+
+// maskName returns varName, or maskedName when it is listed in secrets. Init
+// uses it directly, before the parsed configuration is stored.
+func maskName(secrets []string, varName string) string {
+	if slices.Contains(secrets, varName) {
+		return maskedName
+	}
+	return varName
+}
+
+// processValue expands, renders, converts, and validates a fetched raw value.
+// A non-empty target coerces the expanded value to that type instead of
+// converting it automatically. With emit_conversion_warnings, it also returns
+// notes on ambiguous conversions of the expanded value. ctx is checked before
+// each stage, since the stages themselves are not interruptible. Returned
+// errors are gRPC status errors.
+func (p *Provider) processValue(ctx context.Context, varName, value, target string) (interface{}, string, []string, error) {
+	if err := p.checkDeadline(ctx, varName); err != nil {
+		return nil, "", nil, err
+	}
+
+	// Values that are not valid UTF-8 cannot be carried as strings and skip conversion
+	if !utf8.ValidString(value) {
+		if target != "" && target != "string" {
+			p.logger.Error("cannot coerce %s to %s: value is not valid UTF-8", p.logName(varName), target)
+			return nil, "", nil, status.Errorf(codes.InvalidArgument, "cannot coerce %s to %s: value is not valid UTF-8", varName, target)
+		}
+		converted, typeStr, err := p.encodeBinary(varName, value)
+		return converted, typeStr, nil, err
+	}
+
+	// Diagnostic override: return the raw string without any processing
+	if conversionDisabled() {
+		return value, "string", nil, nil
+	}
+
+	// Expand ${VAR} references before conversion
+	var err error
+	if p.config.ExpandReferences {
+		value, err = resolver.ExpandReferences(value, p.lookupReference, p.config.StrictExpansion, p.indirectionDepth())
+		if err != nil {
+			p.logger.Error("reference expansion failed for %s: %v", p.logName(varName), err)
+			return nil, "", nil, status.Errorf(codes.InvalidArgument, "reference expansion failed for %s: %v", varName, err)
+		}
+	}
+
+	// Render {{.VAR}} templates before conversion
+	if p.config.EnableTemplates {
+		value, err = resolver.RenderTemplate(value, p.lookupReference, p.config.StrictTemplates, p.indirectionDepth())
+		if err != nil {
+			p.logger.Error("template rendering failed for %s: %v", p.logName(varName), err)
+			return nil, "", nil, status.Errorf(codes.InvalidArgument, "template rendering failed for %s: %v", varName, err)
+		}
+	}
+
+	if err := p.checkDeadline(ctx, varName); err != nil {
+		return nil, "", nil, err
+	}
+
+	// Expanded references may have introduced invalid UTF-8
+	if !utf8.ValidString(value) {
+		p.logger.Error("environment variable is not valid UTF-8 after expansion: %s", p.logName(varName))
+		return nil, "", nil, status.Errorf(codes.InvalidArgument, "environment variable %s is not valid UTF-8 after expansion", varName)
+	}
+
+	// Type requested by the client, overriding automatic detection
+	if target != "" {
+		converted, typeStr, err := p.coerceValue(varName, value, target)
+		return converted, typeStr, nil, err
+	}
+
+	// Apply type conversion (a no-op for plain strings when all conversions are disabled)
+	convertedValue, typeStr, err := p.convertValue(varName, value)
+	if err != nil {
+		// Report only the error class; details may quote (secret) parts of the value
+		class := converter.ErrorClass(err)
+		p.logger.Error("type conversion failed for %s: %v", p.logName(varName), class)
+		return nil, "", nil, status.Errorf(codes.InvalidArgument, "type conversion failed for %s: %v", varName, class)
+	}
+
+	// Validate the converted value, whatever its type, against a configured schema
+	if schema, ok := p.config.JSONSchemas[varName]; ok {
+		if p.config.JSONNumbersAsStrings {
+			err = schema.ValidateNumbersAsStrings(convertedValue)
+		} else {
+			err = schema.Validate(convertedValue)
+		}
 		if err != nil {
-			p.logger.Error("type conversion failed for %s: %v", varName, err)
-			return nil, status.Errorf(codes.InvalidArgument, "type conversion failed: %v", err)
+			p.logger.Error("schema validation failed for %s: %v", p.logName(varName), err)
+			return nil, "", nil, status.Errorf(codes.InvalidArgument, "schema validation failed for %s: %v", varName, err)
 		}
-		convertedValue = converted
 	}
 
+	// Render detected booleans in the configured representation
+	if b, ok := convertedValue.(bool); ok {
+		convertedValue, typeStr = p.formatBoolean(b)
+	}
+
+	if err := p.checkDeadline(ctx, varName); err != nil {
+		return nil, "", nil, err
+	}
+
+	// Notes on ambiguous conversions, from the value and options converted above
+	var warnings []string
+	if p.config.EmitConversionWarnings {
+		opts, _ := p.converterOptions(varName)
+		warnings = converter.Warnings(value, opts)
+	}
+
+	return convertedValue, typeStr, warnings, nil
+}
+
+// buildResponse wraps the converted value of varName in a FetchResponse.
+// present reports whether varName itself exists in the environment (false for
+// values assembled from other variables, such as indexed groups). warnings
+// are reported when emit_conversion_warnings is enabled.
+// Returned errors are gRPC status errors.
+func (p *Provider) buildResponse(varName string, present bool, convertedValue interface{}, typeStr string, warnings []string) (*pb.FetchResponse, error) {
 	// Convert value to protobuf Value
 	protoValue, err := toProtoValue(convertedValue)
 	if err != nil {
@@ -97,17 +397,133 @@ func (p *Provider) Fetch(_ context.Context, req *pb.FetchRequest) (*pb.FetchResp
 	}
 
 	// Wrap in a struct with "value" field
-	valueStruct, err := structpb.NewStruct(map[string]interface{}{
+	fields := map[string]interface{}{
 		"value": protoValue,
-	})
+	}
+	if p.config.IncludeType {
+		if mapped, ok := p.config.TypeNameMap[typeStr]; ok {
+			typeStr = mapped
+		}
+		fields["type"] = typeStr
+	}
+	if p.config.IncludeResolvedName {
+		fields["resolved_name"] = varName
+	}
+	if p.config.IncludePresent {
+		fields["present"] = present
+	}
+	if p.config.EmitConversionWarnings {
+		list := make([]interface{}, len(warnings))
+		for i, w := range warnings {
+			list[i] = w
+		}
+		fields["warnings"] = list
+	}
+	valueStruct, err := structpb.NewStruct(fields)
 	if err != nil {
 		p.logger.Error("failed to create protobuf struct: %v", err)
 		return nil, status.Errorf(codes.Internal, "struct creation failed: %v", err)
 	}
 
-	p.logger.Debug("successfully fetched %s", varName)
-
 	return &pb.FetchResponse{
 		Value: valueStruct,
 	}, nil
 }
+
+// RawPathPrefix marks a single-segment path as a literal variable name,
+// e.g. []string{"raw:MYAPP_DATABASE_HOST"}. Raw names bypass separator,
+// case, and prefix handling but still honor the filter_only prefix filter.
+const RawPathPrefix = "raw:"
+
+// rawVariableName returns the literal variable name of a raw path
+func rawVariableName(path []string) (string, bool) {
+	if len(path) != 1 || !strings.HasPrefix(path[0], RawPathPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(path[0], RawPathPrefix), true
+}
+
+// warnIfDeprecated logs the deprecated_variables message for varName, once per name
+func (p *Provider) warnIfDeprecated(varName string) {
+	message, ok := p.config.DeprecatedVariables[varName]
+	if !ok {
+		return
+	}
+	if _, warned := p.deprecatedSeen.LoadOrStore(varName, struct{}{}); warned {
+		return
+	}
+	p.logger.Warn("environment variable %s is deprecated: %s", p.logName(varName), message)
+}
+
+// BypassCacheMetadataKey is the gRPC metadata key a client sets to "true" to
+// have a Fetch read the live environment value without touching the cache.
+const BypassCacheMetadataKey = "x-nomos-bypass-cache"
+
+// bypassCache reports whether the request metadata asks to bypass the cache
+func bypassCache(ctx context.Context) bool {
+	for _, v := range metadata.ValueFromIncomingContext(ctx, BypassCacheMetadataKey) {
+		if enabled, err := strconv.ParseBool(v); err == nil && enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedByPrefix reports whether name passes the filter_only prefix filter.
+// With passthrough_unfiltered the prefix is advisory and every name passes.
+func (p *Provider) allowedByPrefix(name string) bool {
+	if p.config.PrefixMode != "filter_only" || p.config.PassthroughUnfiltered {
+		return true
+	}
+	return resolver.FilterByPrefix(name, p.config.Prefix)
+}
+
+// withRequestTimeout derives a context from ctx bounded by the
+// request_timeout_ms of the current configuration, on top of the client
+// deadline. Without a configuration or timeout, ctx is returned with a no-op
+// cancel. The caller must hold p.mu.
+func (p *Provider) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.config == nil || p.config.RequestTimeoutMS <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(p.config.RequestTimeoutMS)*time.Millisecond)
+}
+
+// checkDeadline reports an error once ctx is done, so a fetch stops between
+// stages after its deadline. Returned errors are gRPC status errors.
+func (p *Provider) checkDeadline(ctx context.Context, varName string) error {
+	if err := ctx.Err(); err != nil {
+		p.logger.Warn("fetch of %s exceeded its deadline: %v", p.logName(varName), err)
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// acquireFetchSlot reserves a slot in the concurrent fetch semaphore and returns
+// a function releasing it. When the limit is reached, it either fails with
+// ResourceExhausted (fail_on_limit) or blocks until a slot frees up or ctx ends.
+func (p *Provider) acquireFetchSlot(ctx context.Context) (func(), error) {
+	slots := p.fetchSlots
+	if slots == nil {
+		return func() {}, nil
+	}
+	release := func() { <-slots }
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if p.config.FailOnLimit {
+		p.logger.Warn("concurrent fetch limit reached (%d)", cap(slots))
+		return nil, status.Errorf(codes.ResourceExhausted, "maximum concurrent fetches (%d) reached", cap(slots))
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
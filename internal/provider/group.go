@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/fetcher"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
+)
+
+// fetchIndexedGroup assembles variables named <group><sep><index>[<sep><field>]
+// into a struct keyed by index. The group name and separator are resolved from
+// path like a nested path, so SERVER_1_HOST and SERVER_2_HOST fetched as
+// ["server"] become {"1": {"host": ...}, "2": {"host": ...}}. A raw path names
+// the group literally. Field keys are mapped back through the case
+// transformation, and an entry with fields takes precedence over a bare
+// SERVER_1 value. Members pass the same checks as directly fetched variables.
+// Returns false if no indexed variables exist. Returned errors are gRPC status errors.
+func (p *Provider) fetchIndexedGroup(ctx context.Context, path []string) (map[string]interface{}, bool, error) {
+	groupPrefix, separator, err := p.groupPrefix(path)
+	if err != nil {
+		return nil, false, err
+	}
+	bare := make(map[string]interface{})
+	entries := make(map[string]map[string]interface{})
+
+	for _, name := range p.fetcher.Names(groupPrefix) {
+		// Secrets are never enumerated into a group
+		if !p.allowedByPrefix(name) || p.isSecret(name) {
+			continue
+		}
+
+		index, field, _ := strings.Cut(name[len(groupPrefix):], separator)
+		if !isIndex(index) {
+			continue
+		}
+
+		value, err := p.readValue(p.fetcher.Fetch, name)
+		if errors.Is(err, fetcher.ErrNotFound) {
+			// Unset between enumeration and fetch, or a null token
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		converted, _, _, err := p.processValue(ctx, name, value, "")
+		if err != nil {
+			return nil, false, err
+		}
+
+		if field == "" {
+			bare[index] = converted
+			continue
+		}
+
+		entry, ok := entries[index]
+		if !ok {
+			entry = make(map[string]interface{})
+			entries[index] = entry
+		}
+		entry[resolver.ReverseTransformSegment(field, p.config.CaseTransform)] = converted
+	}
+
+	group := make(map[string]interface{}, len(bare)+len(entries))
+	for index, value := range bare {
+		group[index] = value
+	}
+	for index, entry := range entries {
+		group[index] = entry
+	}
+
+	return group, len(group) > 0, nil
+}
+
+// groupPrefix resolves the member name prefix and separator of the indexed
+// group at path. Returned errors are gRPC status errors.
+func (p *Provider) groupPrefix(path []string) (prefix, separator string, err error) {
+	if rawName, isRaw := rawVariableName(path); isRaw {
+		return rawName + p.config.Separator, p.config.Separator, nil
+	}
+	prefix, separator, err = p.resolver.GroupPrefix(path)
+	if err != nil {
+		p.logger.Error("group path transformation failed for %v: %v", path, err)
+		return "", "", status.Errorf(codes.InvalidArgument, "path transformation failed: %v", err)
+	}
+	return prefix, separator, nil
+}
+
+// isIndex reports whether s is a non-empty string of ASCII digits
+func isIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -2,31 +2,87 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
 )
 
+// HealthReason is a machine-readable reason code reported as the prefix of
+// the Health message, in the form "<reason>: <description>".
+type HealthReason string
+
+const (
+	// HealthReasonReady indicates the provider is ready to serve requests.
+	HealthReasonReady HealthReason = "ready"
+	// HealthReasonInitializing indicates initialization is in progress.
+	HealthReasonInitializing HealthReason = "initializing"
+	// HealthReasonNotInitialized indicates Init has not completed successfully.
+	HealthReasonNotInitialized HealthReason = "not_initialized"
+	// HealthReasonRequiredMissing indicates the last Init failed because required variables were missing.
+	HealthReasonRequiredMissing HealthReason = "required_missing"
+	// HealthReasonShuttingDown indicates shutdown is in progress.
+	HealthReasonShuttingDown HealthReason = "shutting_down"
+	// HealthReasonStopped indicates the provider has stopped.
+	HealthReasonStopped HealthReason = "stopped"
+)
+
+// ParseHealthReason extracts the reason code from a Health message.
+// Returns an empty reason if the message carries no reason prefix.
+func ParseHealthReason(message string) HealthReason {
+	reason, _, found := strings.Cut(message, ": ")
+	if !found {
+		return ""
+	}
+	return HealthReason(reason)
+}
+
 // Health returns the health status of the provider
 func (p *Provider) Health(_ context.Context, _ *pb.HealthRequest) (*pb.HealthResponse, error) {
+	p.touch()
 	state := p.GetState()
 
 	var status pb.HealthResponse_Status
+	var reason HealthReason
 	var message string
 
 	switch state {
 	case StateReady:
 		status = pb.HealthResponse_STATUS_OK
+		reason = HealthReasonReady
 		message = "provider is ready"
+		// Snapshot under the read lock, as Init swaps config and fetcher
+		p.mu.RLock()
+		cfg, f := p.config, p.fetcher
+		p.mu.RUnlock()
+		if cfg != nil && cfg.HealthIncludeCacheStats && f != nil {
+			entries, _, _ := f.Stats()
+			message = fmt.Sprintf("%s (cache entries: %d)", message, entries)
+		}
 	case StateInitializing:
 		status = pb.HealthResponse_STATUS_STARTING
+		reason = HealthReasonInitializing
 		message = "provider is initializing"
+	case StateShuttingDown:
+		status = pb.HealthResponse_STATUS_DEGRADED
+		reason = HealthReasonShuttingDown
+		message = "provider is shutting down"
+	case StateStopped:
+		status = pb.HealthResponse_STATUS_DEGRADED
+		reason = HealthReasonStopped
+		message = "provider is stopped"
 	default:
 		status = pb.HealthResponse_STATUS_DEGRADED
+		reason = HealthReasonNotInitialized
 		message = "provider is not ready"
+		if p.requiredMissing.Load() {
+			reason = HealthReasonRequiredMissing
+			message = "required environment variables missing"
+		}
 	}
 
 	return &pb.HealthResponse{
 		Status:  status,
-		Message: message,
+		Message: fmt.Sprintf("%s: %s", reason, message),
 	}, nil
 }
@@ -6,14 +6,24 @@ import (
 	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
 )
 
+// DefaultType is the provider type reported by Info unless overridden by
+// the type_override config option
+const DefaultType = "environment-variables"
+
 // Info returns provider metadata
 func (p *Provider) Info(_ context.Context, _ *pb.InfoRequest) (*pb.InfoResponse, error) {
+	p.touch()
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
+	providerType := DefaultType
+	if p.config != nil && p.config.TypeOverride != "" {
+		providerType = p.config.TypeOverride
+	}
+
 	return &pb.InfoResponse{
 		Alias:   p.alias,
 		Version: Version,
-		Type:    "environment-variables",
+		Type:    providerType,
 	}, nil
 }
@@ -2,8 +2,7 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"slices"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -16,11 +15,13 @@ import (
 
 // Init initializes the provider with configuration
 func (p *Provider) Init(_ context.Context, req *pb.InitRequest) (*pb.InitResponse, error) {
+	p.touch()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	p.logger.Info("initializing provider with alias: %s", req.Alias)
 	p.setState(StateInitializing)
+	p.requiredMissing.Store(false)
 
 	// Parse configuration
 	cfg, err := config.ParseConfig(req.Config)
@@ -37,19 +38,94 @@ func (p *Provider) Init(_ context.Context, req *pb.InitRequest) (*pb.InitRespons
 		return nil, status.Errorf(codes.InvalidArgument, "config validation failed: %v", err)
 	}
 
+	// Variables are read from the process environment and injected_variables
+	source := envSource(cfg)
+
 	// Validate required variables exist
 	if len(cfg.RequiredVariables) > 0 {
 		var missing []string
 		for _, varName := range cfg.RequiredVariables {
-			if _, exists := os.LookupEnv(varName); !exists {
+			if _, exists := source.Lookup(varName); !exists {
 				missing = append(missing, varName)
 			}
 		}
 		if len(missing) > 0 {
+			p.requiredMissing.Store(true)
+			p.setState(StateUninitialized)
+			logged := make([]string, len(missing))
+			for i, varName := range missing {
+				logged[i] = maskName(cfg.SecretVariables, varName)
+			}
+			p.logger.Error("required environment variables missing: %v", logged)
+			return nil, status.Errorf(codes.InvalidArgument, "required environment variables missing: %v", missing)
+		}
+	}
+
+	// Validate length constraints of required variables
+	for _, varName := range cfg.RequiredVariables {
+		constraint, ok := cfg.VariableConstraints[varName]
+		if !ok {
+			continue
+		}
+		value, _ := source.Lookup(varName)
+		if err := checkLength(varName, value, constraint); err != nil {
 			p.setState(StateUninitialized)
-			errMsg := fmt.Sprintf("required environment variables missing: %v", missing)
-			p.logger.Error("%s", errMsg)
-			return nil, status.Error(codes.InvalidArgument, errMsg)
+			p.logger.Error("length constraint violated: %s", maskName(cfg.SecretVariables, varName))
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// Validate conditionally required variables
+	for _, rule := range cfg.ConditionalRequirements {
+		if value, ok := source.Lookup(rule.WhenVariable); !ok || value != rule.WhenEquals {
+			continue
+		}
+		if _, exists := source.Lookup(rule.Require); !exists {
+			p.requiredMissing.Store(true)
+			p.setState(StateUninitialized)
+			// The condition value is omitted from the log as it may belong to a secret
+			p.logger.Error("required environment variable missing: %s (required by a condition on %s)", maskName(cfg.SecretVariables, rule.Require), maskName(cfg.SecretVariables, rule.WhenVariable))
+			return nil, status.Errorf(codes.InvalidArgument, "required environment variable missing: %s (required when %s=%q)", rule.Require, rule.WhenVariable, rule.WhenEquals)
+		}
+	}
+
+	// Create resolver with configured separator, case transformation, prefix, and prefix mode
+	res := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:           cfg.Separator,
+		CaseTransform:       cfg.CaseTransform,
+		Prefix:              cfg.Prefix,
+		PrefixMode:          cfg.PrefixMode,
+		PrefixSeparator:     cfg.PrefixSeparator,
+		AutoPrefixSeparator: cfg.AutoPrefixSeparator,
+		SegmentTransforms:   cfg.SegmentTransforms,
+		ScreamingChars:      cfg.ScreamingChars,
+		CollapseSeparators:  cfg.CollapseSeparators,
+		CamelSplit:          cfg.CamelSplit,
+		NameCharMap:         cfg.NameCharMap,
+		JoinSeparator:       cfg.JoinSeparator,
+		DropEmptySegments:   cfg.DropEmptySegments,
+		PrefixSeparators:    cfg.PrefixSeparators,
+	})
+
+	// Validate that every declared path resolves to an existing variable
+	if cfg.DeclaredPathsRequired {
+		for _, path := range cfg.DeclaredPaths {
+			varName, _, err := resolvePath(res, path)
+			if err != nil {
+				p.setState(StateUninitialized)
+				p.logger.Error("declared path %v: %v", path, err)
+				return nil, err
+			}
+			if _, exists := source.Lookup(varName); !exists {
+				p.requiredMissing.Store(true)
+				p.setState(StateUninitialized)
+				if slices.Contains(cfg.SecretVariables, varName) {
+					p.logger.Error("declared path: environment variable missing: %s", maskedName)
+				} else {
+					p.logger.Error("declared path %v: environment variable missing: %s", path, varName)
+				}
+				return nil, status.Errorf(codes.InvalidArgument, "declared path %v: environment variable missing: %s", path, varName)
+			}
 		}
 	}
 
@@ -57,16 +133,49 @@ func (p *Provider) Init(_ context.Context, req *pb.InitRequest) (*pb.InitRespons
 	p.config = cfg
 	p.alias = req.Alias
 
-	// Create fetcher if not exists
+	// Create fetcher if not exists; a re-Init always starts from an empty
+	// cache so values cached under a previous configuration are not served
 	if p.fetcher == nil {
-		p.fetcher = fetcher.New()
+		p.fetcher = fetcher.NewWithSource(source)
+	} else {
+		p.fetcher.Clear()
+		p.fetcher.SetSource(source)
 	}
+	p.fetcher.SetCacheNegative(cfg.CacheNegative)
+	p.fetcher.SetCaseInsensitive(cfg.CaseInsensitiveLookup)
+	p.resolver = res
 
-	// Create resolver with configured separator, case transformation, prefix, and prefix mode
-	p.resolver = resolver.NewResolver(cfg.Separator, cfg.CaseTransform, cfg.Prefix, cfg.PrefixMode)
+	// Create the concurrent fetch semaphore (zero means unlimited)
+	p.fetchSlots = nil
+	if cfg.MaxConcurrentFetches > 0 {
+		p.fetchSlots = make(chan struct{}, cfg.MaxConcurrentFetches)
+	}
+
+	// Create a fresh conversion cache, since cached results depend on the config
+	p.convCache = nil
+	if cfg.ConversionCacheSize > 0 {
+		p.convCache = newConversionCache(cfg.ConversionCacheSize)
+	}
 
 	p.setState(StateReady)
 	p.logger.Info("provider initialized successfully")
 
 	return &pb.InitResponse{}, nil
 }
+
+// InjectedSourceName is the source name of injected_variables.
+const InjectedSourceName = "injected"
+
+// envSource returns the source variables are read from: the process
+// environment, chained with injected_variables when configured. Injected
+// values are consulted last unless injected_variables_override is set.
+func envSource(cfg *config.Config) fetcher.EnvSource {
+	if len(cfg.InjectedVariables) == 0 {
+		return fetcher.ProcessEnv()
+	}
+	injected := fetcher.NewMapSource(InjectedSourceName, cfg.InjectedVariables)
+	if cfg.InjectedVariablesOverride {
+		return fetcher.NewChainedEnvSource(injected, fetcher.ProcessEnv())
+	}
+	return fetcher.NewChainedEnvSource(fetcher.ProcessEnv(), injected)
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+)
+
+// LoggingInterceptor returns a unary server interceptor that logs the method
+// name, duration, and resulting status code of every RPC at info level.
+// It is opt-in and attached by the server via grpc.ChainUnaryInterceptor.
+func LoggingInterceptor(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.Info("rpc %s completed in %s with status %s", info.FullMethod, time.Since(start), status.Code(err))
+		return resp, err
+	}
+}
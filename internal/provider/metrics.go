@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxCode is the highest gRPC status code tracked by the error counters
+const maxCode = codes.Unauthenticated
+
+// metrics holds the provider's cumulative request counters
+type metrics struct {
+	fetches atomic.Uint64
+	errors  [maxCode + 1]atomic.Uint64 // indexed by gRPC status code
+}
+
+// recordFetch counts a completed Fetch and, if err is non-nil, its status code
+func (m *metrics) recordFetch(err error) {
+	m.fetches.Add(1)
+	if err == nil {
+		return
+	}
+	code := status.Code(err)
+	if code > maxCode {
+		code = codes.Unknown
+	}
+	m.errors[code].Add(1)
+}
+
+// WriteMetrics writes the provider metrics to w in the Prometheus text
+// exposition format
+func (p *Provider) WriteMetrics(w io.Writer) error {
+	var entries int
+	var hits, misses uint64
+	p.mu.RLock()
+	if p.fetcher != nil {
+		entries, hits, misses = p.fetcher.Stats()
+	}
+	p.mu.RUnlock()
+
+	var ratio float64
+	if hits+misses > 0 {
+		ratio = float64(hits) / float64(hits+misses)
+	}
+
+	ew := &errWriter{w: w}
+	ew.printf("# HELP nomos_env_fetch_total Total number of Fetch requests.\n")
+	ew.printf("# TYPE nomos_env_fetch_total counter\n")
+	ew.printf("nomos_env_fetch_total %d\n", p.metrics.fetches.Load())
+	ew.printf("# HELP nomos_env_fetch_errors_total Failed Fetch requests by gRPC status code.\n")
+	ew.printf("# TYPE nomos_env_fetch_errors_total counter\n")
+	for code := codes.Canceled; code <= maxCode; code++ {
+		if n := p.metrics.errors[code].Load(); n > 0 {
+			ew.printf("nomos_env_fetch_errors_total{code=%q} %d\n", code.String(), n)
+		}
+	}
+	ew.printf("# HELP nomos_env_cache_hits_total Fetcher cache hits.\n")
+	ew.printf("# TYPE nomos_env_cache_hits_total counter\n")
+	ew.printf("nomos_env_cache_hits_total %d\n", hits)
+	ew.printf("# HELP nomos_env_cache_misses_total Fetcher cache misses.\n")
+	ew.printf("# TYPE nomos_env_cache_misses_total counter\n")
+	ew.printf("nomos_env_cache_misses_total %d\n", misses)
+	ew.printf("# HELP nomos_env_cache_hit_ratio Fraction of fetcher lookups served from cache.\n")
+	ew.printf("# TYPE nomos_env_cache_hit_ratio gauge\n")
+	ew.printf("nomos_env_cache_hit_ratio %g\n", ratio)
+	ew.printf("# HELP nomos_env_cache_entries Variables currently held in the fetcher cache.\n")
+	ew.printf("# TYPE nomos_env_cache_entries gauge\n")
+	ew.printf("nomos_env_cache_entries %d\n", entries)
+	return ew.err
+}
+
+// MetricsHandler returns an HTTP handler serving WriteMetrics
+func (p *Provider) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := p.WriteMetrics(w); err != nil {
+			p.logger.Warn("failed to write metrics: %v", err)
+		}
+	})
+}
+
+// errWriter formats to an io.Writer, keeping the first error
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
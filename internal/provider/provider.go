@@ -3,6 +3,7 @@ package provider
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/config"
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/fetcher"
@@ -36,9 +37,15 @@ type Provider struct {
 	fetcher  *fetcher.Fetcher
 	resolver *resolver.Resolver
 	// cache   sync.Map // Reserved for future use
-	state  atomic.Int32
-	logger *logger.Logger
-	mu     sync.RWMutex
+	fetchSlots      chan struct{}    // nil when concurrent fetches are unlimited
+	convCache       *conversionCache // nil when conversion caching is disabled
+	requiredMissing atomic.Bool      // last Init failed on missing required variables
+	metrics         metrics
+	deprecatedSeen  sync.Map     // deprecated variable names already warned about
+	lastActivity    atomic.Int64 // unix nanoseconds of the last RPC
+	state           atomic.Int32
+	logger          *logger.Logger
+	mu              sync.RWMutex
 }
 
 // New creates a new Provider instance
@@ -47,9 +54,21 @@ func New(log *logger.Logger) *Provider {
 		logger: log,
 	}
 	p.state.Store(int32(StateUninitialized))
+	p.touch()
 	return p
 }
 
+// touch records RPC activity for idle tracking
+func (p *Provider) touch() {
+	p.lastActivity.Store(time.Now().UnixNano())
+}
+
+// LastActivity returns the time of the most recent RPC, or of creation if
+// no RPC has been handled yet
+func (p *Provider) LastActivity() time.Time {
+	return time.Unix(0, p.lastActivity.Load())
+}
+
 // GetState returns the current provider state
 func (p *Provider) GetState() State {
 	return State(p.state.Load())
@@ -8,6 +8,7 @@ import (
 
 // Shutdown gracefully shuts down the provider
 func (p *Provider) Shutdown(_ context.Context, _ *pb.ShutdownRequest) (*pb.ShutdownResponse, error) {
+	p.touch()
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -18,6 +19,9 @@ func (p *Provider) Shutdown(_ context.Context, _ *pb.ShutdownRequest) (*pb.Shutd
 	if p.fetcher != nil {
 		p.fetcher.Clear()
 	}
+	if p.convCache != nil {
+		p.convCache.clear()
+	}
 
 	p.setState(StateStopped)
 	p.logger.Info("provider shut down successfully")
@@ -0,0 +1,80 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnresolvedReference is returned in strict mode when a ${VAR} reference cannot be resolved
+	ErrUnresolvedReference = errors.New("unresolved variable reference")
+	// ErrExpansionTooDeep is returned when nested references exceed the maximum depth
+	ErrExpansionTooDeep = errors.New("variable reference expansion exceeds maximum depth")
+)
+
+// DefaultMaxExpansionDepth is the default maximum nesting depth for reference expansion.
+const DefaultMaxExpansionDepth = 10
+
+// LookupFunc looks up the value of a variable by name.
+type LookupFunc func(name string) (string, bool)
+
+// ExpandReferences substitutes ${VAR} references in value using lookup.
+// Referenced values are expanded recursively, up to maxDepth levels, which
+// also guards against reference cycles. Unresolved references are left as-is
+// unless strict is set, in which case ErrUnresolvedReference is returned.
+//
+// Example: "postgres://${DB_HOST}:${DB_PORT}/app" with DB_HOST=localhost and
+// DB_PORT=5432 returns "postgres://localhost:5432/app".
+func ExpandReferences(value string, lookup LookupFunc, strict bool, maxDepth int) (string, error) {
+	return expandReferences(value, lookup, strict, maxDepth, 0)
+}
+
+// expandReferences performs expansion at the given nesting depth
+func expandReferences(value string, lookup LookupFunc, strict bool, maxDepth, depth int) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	if depth >= maxDepth {
+		return "", fmt.Errorf("%w (%d)", ErrExpansionTooDeep, maxDepth)
+	}
+
+	var builder strings.Builder
+	rest := value
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			builder.WriteString(rest)
+			break
+		}
+		end := strings.Index(rest[start+2:], "}")
+		if end < 0 {
+			// No closing brace: keep the remainder verbatim
+			builder.WriteString(rest)
+			break
+		}
+		end += start + 2
+
+		builder.WriteString(rest[:start])
+		name := rest[start+2 : end]
+		reference := rest[start : end+1]
+		rest = rest[end+1:]
+
+		resolved, ok := lookup(name)
+		if !ok {
+			if strict {
+				return "", fmt.Errorf("%w: %s", ErrUnresolvedReference, reference)
+			}
+			builder.WriteString(reference)
+			continue
+		}
+
+		expanded, err := expandReferences(resolved, lookup, strict, maxDepth, depth+1)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(expanded)
+	}
+
+	return builder.String(), nil
+}
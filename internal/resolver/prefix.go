@@ -33,6 +33,32 @@ func PrependPrefix(varName, prefix string) string {
 	return prefix + varName
 }
 
+// JoinPrefix prepends prefix to name, controlling the separator at the seam.
+//
+// When prefixSeparator is non-empty, it is placed exactly once between prefix
+// and name: one trailing prefixSeparator is trimmed from the prefix and one
+// leading prefixSeparator from the name before joining.
+//
+// When prefixSeparator is empty, the prefix is prepended as-is, except that a
+// doubled separator at the seam (prefix ending with separator and name
+// beginning with it) is collapsed to one. No separator is inserted, so
+// "MYAPP" + "DATABASE_HOST" stays "MYAPPDATABASE_HOST"; this keeps prefixes
+// configured without a trailing separator resolving as before. Resolvers
+// created with Options.AutoPrefixSeparator pass separator as prefixSeparator
+// to insert a missing one.
+func JoinPrefix(prefix, name, separator, prefixSeparator string) string {
+	if prefix == "" {
+		return name
+	}
+	if prefixSeparator != "" {
+		return strings.TrimSuffix(prefix, prefixSeparator) + prefixSeparator + strings.TrimPrefix(name, prefixSeparator)
+	}
+	if separator != "" && strings.HasSuffix(prefix, separator) && strings.HasPrefix(name, separator) {
+		return prefix + strings.TrimPrefix(name, separator)
+	}
+	return prefix + name
+}
+
 // FilterByPrefix checks if a variable name has the required prefix.
 // Returns true if the variable should be accessible, false otherwise.
 // If no prefix is configured (empty string), all variables are allowed.
@@ -2,6 +2,7 @@ package resolver
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -10,15 +11,77 @@ var (
 	ErrEmptyPath = errors.New("path cannot be empty")
 	// ErrEmptySegment is returned when a path contains an empty segment
 	ErrEmptySegment = errors.New("path segments cannot be empty")
+	// ErrMissingSeparator is returned when a multi-segment path resolves to a
+	// name without a separator, making it indistinguishable from a single
+	// segment, e.g. when name_char_map replaces the separator with ""
+	ErrMissingSeparator = errors.New("multi-segment path resolved to a name without a separator")
 )
 
 // Resolver transforms hierarchical paths into environment variable names
 // using configurable separator, case conversion, and prefix handling.
 type Resolver struct {
-	separator     string
-	caseTransform string
-	prefix        string
-	prefixMode    string
+	separator         string
+	caseTransform     string
+	prefix            string
+	prefixMode        string
+	prefixSeparator   string
+	segmentTransforms []string
+	screamingChars    string
+	collapse          bool
+	camelSplit        bool
+	nameChars         *strings.Replacer
+	joinSeparator     string
+	dropEmpty         bool
+	prefixSeparators  map[string]string
+}
+
+// Options configures a Resolver created with NewResolverWithOptions.
+type Options struct {
+	// Separator joins path segments.
+	Separator string
+	// CaseTransform is applied to each segment ("upper", "lower", or "preserve").
+	CaseTransform string
+	// Prefix is the prefix to apply.
+	Prefix string
+	// PrefixMode controls prefix behavior ("prepend" or "filter_only").
+	PrefixMode string
+	// PrefixSeparator, when non-empty, is placed exactly once between the
+	// prefix and the name in prepend mode. See JoinPrefix.
+	PrefixSeparator string
+	// AutoPrefixSeparator places Separator exactly once between a non-empty
+	// prefix and the name in prepend mode when PrefixSeparator is unset, so a
+	// prefix missing its trailing separator still yields "MYAPP_DATABASE_HOST".
+	// With neither set, the prefix is prepended as-is for compatibility.
+	AutoPrefixSeparator bool
+	// SegmentTransforms, when non-empty, overrides CaseTransform with a
+	// transformation per path position. See TransformAt.
+	SegmentTransforms []string
+	// ScreamingChars are replaced by Separator in segments using the
+	// "screaming" transformation. Empty means DefaultScreamingChars.
+	ScreamingChars string
+	// CollapseSeparators replaces runs of Separator in the resolved name with
+	// a single separator. See CollapseSeparators.
+	CollapseSeparators bool
+	// CamelSplit inserts Separator at camelCase boundaries in each segment
+	// before case transformation. See CamelSplit.
+	CamelSplit bool
+	// NameCharMap replaces characters in the resolved name (after prefixing),
+	// e.g. {".": "_"} for platforms that disallow dots in variable names.
+	// Keys are single characters. See MapNameChars.
+	NameCharMap map[string]string
+	// JoinSeparator, when non-empty, splits path segments on it before
+	// resolution, so "path/to" is read as ["path", "to"] and resolves to
+	// "PATH_TO" with Separator "_". It never appears in resolved names.
+	JoinSeparator string
+	// DropEmptySegments removes empty and whitespace-only segments instead of
+	// rejecting the path, so ["app", "", "host"] resolves to "APP_HOST". A path
+	// with only empty segments is still rejected.
+	DropEmptySegments bool
+	// PrefixSeparators maps a leading path segment (matched
+	// case-insensitively) to the separator used instead of Separator for
+	// paths starting with it, e.g. {"app1": "_", "app2": "-"}. Only applies in
+	// filter_only mode, where the prefix is part of the path.
+	PrefixSeparators map[string]string
 }
 
 // NewResolver creates a new Resolver with the specified configuration.
@@ -27,11 +90,41 @@ type Resolver struct {
 // prefix is the prefix to apply, and prefixMode controls prefix behavior
 // ("prepend" or "filter_only").
 func NewResolver(separator, caseTransform, prefix, prefixMode string) *Resolver {
+	return NewResolverWithOptions(Options{
+		Separator:     separator,
+		CaseTransform: caseTransform,
+		Prefix:        prefix,
+		PrefixMode:    prefixMode,
+	})
+}
+
+// NewResolverWithOptions creates a new Resolver from the given options.
+func NewResolverWithOptions(opts Options) *Resolver {
+	if opts.AutoPrefixSeparator && opts.PrefixSeparator == "" {
+		opts.PrefixSeparator = opts.Separator
+	}
+	var nameChars *strings.Replacer
+	if len(opts.NameCharMap) > 0 {
+		pairs := make([]string, 0, 2*len(opts.NameCharMap))
+		for char, replacement := range opts.NameCharMap {
+			pairs = append(pairs, char, replacement)
+		}
+		nameChars = strings.NewReplacer(pairs...)
+	}
 	return &Resolver{
-		separator:     separator,
-		caseTransform: caseTransform,
-		prefix:        prefix,
-		prefixMode:    prefixMode,
+		separator:         opts.Separator,
+		caseTransform:     opts.CaseTransform,
+		prefix:            opts.Prefix,
+		prefixMode:        opts.PrefixMode,
+		prefixSeparator:   opts.PrefixSeparator,
+		segmentTransforms: opts.SegmentTransforms,
+		screamingChars:    opts.ScreamingChars,
+		collapse:          opts.CollapseSeparators,
+		camelSplit:        opts.CamelSplit,
+		nameChars:         nameChars,
+		joinSeparator:     opts.JoinSeparator,
+		dropEmpty:         opts.DropEmptySegments,
+		prefixSeparators:  opts.PrefixSeparators,
 	}
 }
 
@@ -43,13 +136,19 @@ func NewResolver(separator, caseTransform, prefix, prefixMode string) *Resolver
 // prefix="MYAPP_", and mode="prepend" returns "MYAPP_DATABASE_HOST".
 //
 // Returns an error if the path is empty, contains empty segments, or
-// prefix mode is invalid.
+// a multi-segment path resolves to a name without the separator.
 func (r *Resolver) Transform(path []string) (string, error) {
 	// Validate path is not empty
 	if len(path) == 0 {
 		return "", ErrEmptyPath
 	}
 
+	// Split on the path-only separator and drop empty segments when configured
+	path = r.NormalizePath(path)
+	if len(path) == 0 {
+		return "", ErrEmptySegment
+	}
+
 	// Validate no segments are empty or only whitespace
 	for i, segment := range path {
 		if strings.TrimSpace(segment) == "" {
@@ -59,14 +158,124 @@ func (r *Resolver) Transform(path []string) (string, error) {
 		path[i] = segment
 	}
 
-	// Transform all segments
-	transformed := TransformSegments(path, r.caseTransform)
+	// Transform all segments, per position when configured
+	separator := r.separatorFor(path)
+	transformed := make([]string, len(path))
+	for i, segment := range path {
+		caseTransform := r.caseTransform
+		if len(r.segmentTransforms) > 0 {
+			caseTransform = TransformAt(r.segmentTransforms, i)
+		}
+		transformed[i] = r.transformSegment(segment, caseTransform, separator)
+	}
 
 	// Join with separator
-	transformedName := strings.Join(transformed, r.separator)
+	transformedName := strings.Join(transformed, separator)
+
+	// Apply prefix based on mode (prepend normalizes the separator at the seam)
+	var varName string
+	if r.prefixMode == "prepend" {
+		varName = JoinPrefix(r.prefix, transformedName, r.separator, r.prefixSeparator)
+	} else {
+		varName = ApplyPrefix(transformedName, r.prefix, r.prefixMode)
+	}
 
-	// Apply prefix based on mode
-	varName := ApplyPrefix(transformedName, r.prefix, r.prefixMode)
+	if r.collapse {
+		varName = CollapseSeparators(varName, separator)
+	}
+	varName = r.MapNameChars(varName)
+
+	// A multi-segment path must keep its segments distinguishable in the final
+	// name, e.g. name_char_map must not delete the separator
+	if mapped := r.MapNameChars(separator); len(path) > 1 && (mapped == "" || !strings.Contains(varName, mapped)) {
+		return "", fmt.Errorf("%w: %v joined with separator %q gives %q", ErrMissingSeparator, path, separator, varName)
+	}
 
 	return varName, nil
 }
+
+// GroupPrefix returns the name prefix shared by the members of the indexed
+// group at path (the transformed path followed by its separator) and the
+// separator between a member's index and field.
+func (r *Resolver) GroupPrefix(path []string) (prefix, separator string, err error) {
+	name, err := r.Transform(path)
+	if err != nil {
+		return "", "", err
+	}
+	separator = r.separatorFor(r.NormalizePath(path))
+	return name + separator, separator, nil
+}
+
+// separatorFor returns the separator for a normalized path: the
+// PrefixSeparators entry matching its first segment in filter_only mode, or
+// the configured Separator.
+func (r *Resolver) separatorFor(path []string) string {
+	if r.prefixMode != "filter_only" {
+		return r.separator
+	}
+	for prefix, separator := range r.prefixSeparators {
+		if strings.EqualFold(path[0], prefix) {
+			return separator
+		}
+	}
+	return r.separator
+}
+
+// NormalizePath splits each segment of path on the configured JoinSeparator
+// and, with DropEmptySegments, removes empty and whitespace-only segments.
+// Without either option, path is returned unchanged.
+func (r *Resolver) NormalizePath(path []string) []string {
+	if r.joinSeparator == "" && !r.dropEmpty {
+		return path
+	}
+	normalized := make([]string, 0, len(path))
+	for _, segment := range path {
+		parts := []string{segment}
+		if r.joinSeparator != "" {
+			parts = strings.Split(segment, r.joinSeparator)
+		}
+		for _, part := range parts {
+			if r.dropEmpty && strings.TrimSpace(part) == "" {
+				continue
+			}
+			normalized = append(normalized, part)
+		}
+	}
+	return normalized
+}
+
+// MapNameChars applies the configured NameCharMap to a variable name. It is
+// applied by Transform and exported for names that bypass it, such as
+// single-segment paths.
+func (r *Resolver) MapNameChars(name string) string {
+	if r.nameChars == nil {
+		return name
+	}
+	return r.nameChars.Replace(name)
+}
+
+// transformSegment applies caseTransform to a segment, replacing the screaming
+// characters with separator for the "screaming" transformation.
+// With camel splitting enabled, camelCase boundaries are separated first.
+func (r *Resolver) transformSegment(segment, caseTransform, separator string) string {
+	if r.camelSplit {
+		segment = CamelSplit(segment, separator)
+	}
+	if caseTransform != "screaming" || r.screamingChars == "" {
+		return TransformSegment(segment, caseTransform, separator)
+	}
+	return ScreamingCase(segment, separator, r.screamingChars)
+}
+
+// CollapseSeparators replaces every run of separator in name with a single
+// separator, e.g. "DB__HOST" becomes "DB_HOST" for separator "_".
+func CollapseSeparators(name, separator string) string {
+	if separator == "" {
+		return name
+	}
+	double := separator + separator
+	for strings.Contains(name, double) {
+		name = strings.ReplaceAll(name, double, separator)
+	}
+	return name
+}
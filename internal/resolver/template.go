@@ -0,0 +1,127 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+var (
+	// ErrInvalidTemplate is returned when a value cannot be parsed or executed as a template
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrMissingTemplateKey is returned in strict mode when a template references an unknown variable
+	ErrMissingTemplateKey = errors.New("missing template key")
+)
+
+// RenderTemplate renders value as a text/template when it contains "{{".
+// The data context maps the variable names referenced by the template (as
+// {{.NAME}}) to their values from lookup. Referenced values that are
+// templates themselves are rendered recursively, up to maxDepth levels, which
+// also guards against cycles. Unknown variables render as empty strings
+// unless strict is set, in which case ErrMissingTemplateKey is returned.
+//
+// Example: "Hello {{.USER}}" with USER=alice returns "Hello alice".
+func RenderTemplate(value string, lookup LookupFunc, strict bool, maxDepth int) (string, error) {
+	return renderTemplate(value, lookup, strict, maxDepth, 0)
+}
+
+// renderTemplate performs rendering at the given nesting depth
+func renderTemplate(value string, lookup LookupFunc, strict bool, maxDepth, depth int) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	if depth >= maxDepth {
+		return "", fmt.Errorf("%w (%d)", ErrExpansionTooDeep, maxDepth)
+	}
+
+	missingKey := "missingkey=zero"
+	if strict {
+		missingKey = "missingkey=error"
+	}
+	// Parse errors may quote the value, so only the error class is reported
+	tmpl, err := template.New("value").Option(missingKey).Parse(value)
+	if err != nil {
+		return "", ErrInvalidTemplate
+	}
+
+	names := make(map[string]struct{})
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectTemplateFields(t.Tree.Root, names)
+		}
+	}
+
+	data := make(map[string]string, len(names))
+	for name := range names {
+		resolved, ok := lookup(name)
+		if !ok {
+			if strict {
+				return "", fmt.Errorf("%w: %s", ErrMissingTemplateKey, name)
+			}
+			continue
+		}
+		rendered, err := renderTemplate(resolved, lookup, strict, maxDepth, depth+1)
+		if err != nil {
+			return "", err
+		}
+		data[name] = rendered
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", ErrInvalidTemplate
+	}
+	return builder.String(), nil
+}
+
+// collectTemplateFields adds the top-level field names referenced by node
+// (.NAME or $.NAME) to names
+func collectTemplateFields(node parse.Node, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectTemplateFields(child, names)
+		}
+	case *parse.ActionNode:
+		collectTemplateFields(n.Pipe, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectTemplateFields(cmd, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectTemplateFields(arg, names)
+		}
+	case *parse.ChainNode:
+		collectTemplateFields(n.Node, names)
+	case *parse.FieldNode:
+		names[n.Ident[0]] = struct{}{}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			names[n.Ident[1]] = struct{}{}
+		}
+	case *parse.IfNode:
+		collectBranchFields(&n.BranchNode, names)
+	case *parse.RangeNode:
+		collectBranchFields(&n.BranchNode, names)
+	case *parse.WithNode:
+		collectBranchFields(&n.BranchNode, names)
+	case *parse.TemplateNode:
+		collectTemplateFields(n.Pipe, names)
+	}
+}
+
+// collectBranchFields collects the fields of an if, range, or with node
+func collectBranchFields(n *parse.BranchNode, names map[string]struct{}) {
+	collectTemplateFields(n.Pipe, names)
+	collectTemplateFields(n.List, names)
+	collectTemplateFields(n.ElseList, names)
+}
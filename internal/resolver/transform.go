@@ -1,6 +1,9 @@
 package resolver
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
 
 // ToUpperCase converts a string to uppercase using Unicode case mapping.
 func ToUpperCase(s string) string {
@@ -17,12 +20,50 @@ func PreserveCase(s string) string {
 	return s
 }
 
+// DefaultScreamingChars are the characters the "screaming" transformation
+// replaces with the separator.
+const DefaultScreamingChars = "-."
+
+// ScreamingCase uppercases s and replaces every character in chars with separator,
+// so "api-v2" becomes "API_V2" with separator "_".
+func ScreamingCase(s, separator, chars string) string {
+	pairs := make([]string, 0, 2*len(chars))
+	for _, r := range chars {
+		pairs = append(pairs, string(r), separator)
+	}
+	return strings.NewReplacer(pairs...).Replace(ToUpperCase(s))
+}
+
+// CamelSplit inserts separator at camelCase word boundaries: before an upper
+// case letter that follows a lower case letter or digit, and before the last
+// letter of an acronym followed by a lower case letter. So "apiKey" becomes
+// "api_Key", "HTTPServer" becomes "HTTP_Server", and "myAPIEndpoint" becomes
+// "my_API_Endpoint" with separator "_".
+func CamelSplit(s, separator string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteString(separator)
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // TransformSegment applies the specified case transformation to a single path segment.
-// Valid transformations are "upper", "lower", and "preserve".
-func TransformSegment(segment, caseTransform string) string {
+// Valid transformations are "upper", "lower", "preserve", and "screaming"
+// (uppercase with DefaultScreamingChars replaced by separator; see ScreamingCase).
+func TransformSegment(segment, caseTransform, separator string) string {
 	switch caseTransform {
 	case "upper":
 		return ToUpperCase(segment)
+	case "screaming":
+		return ScreamingCase(segment, separator, DefaultScreamingChars)
 	case "lower":
 		return ToLowerCase(segment)
 	case "preserve":
@@ -35,14 +76,39 @@ func TransformSegment(segment, caseTransform string) string {
 
 // TransformSegments applies the specified case transformation to all path segments.
 // Returns a new slice with transformed segments.
-func TransformSegments(segments []string, caseTransform string) []string {
+func TransformSegments(segments []string, caseTransform, separator string) []string {
 	if len(segments) == 0 {
 		return []string{}
 	}
 
 	transformed := make([]string, len(segments))
 	for i, segment := range segments {
-		transformed[i] = TransformSegment(segment, caseTransform)
+		transformed[i] = TransformSegment(segment, caseTransform, separator)
 	}
 	return transformed
 }
+
+// TransformAt returns the transformation for path position i: transforms[i],
+// the last transformation beyond the end of transforms, or "preserve" when
+// transforms is empty.
+func TransformAt(transforms []string, i int) string {
+	switch {
+	case len(transforms) == 0:
+		return "preserve"
+	case i < len(transforms):
+		return transforms[i]
+	default:
+		return transforms[len(transforms)-1]
+	}
+}
+
+// ReverseTransformSegment maps a variable name segment back to the path segment
+// a user would pass for the given case transformation. Uppercased names
+// (upper and screaming) are lowercased; lower and preserve return the segment
+// unchanged.
+func ReverseTransformSegment(segment, caseTransform string) string {
+	if caseTransform == "upper" || caseTransform == "screaming" {
+		return ToLowerCase(segment)
+	}
+	return segment
+}
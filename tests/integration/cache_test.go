@@ -0,0 +1,130 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Integration test for bypassing the fetcher cache via request metadata
+func TestFetchBypassCache(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_BYPASS_CACHE_%d", time.Now().UnixNano())
+	setEnv(t, varName, "original")
+	initWithConfig(ctx, t, client, map[string]interface{}{})
+
+	fetchValue := func(ctx context.Context) string {
+		t.Helper()
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		return resp.Value.Fields["value"].GetStringValue()
+	}
+	bypassCtx := metadata.AppendToOutgoingContext(ctx, provider.BypassCacheMetadataKey, "true")
+
+	// Populate the cache, then change the variable
+	if got := fetchValue(ctx); got != "original" {
+		t.Fatalf("initial fetch got %q, want %q", got, "original")
+	}
+	setEnv(t, varName, "updated")
+
+	if got := fetchValue(ctx); got != "original" {
+		t.Errorf("cached fetch got %q, want %q", got, "original")
+	}
+	if got := fetchValue(bypassCtx); got != "updated" {
+		t.Errorf("bypass fetch got %q, want %q", got, "updated")
+	}
+
+	// Bypassing does not refresh the cache
+	if got := fetchValue(ctx); got != "original" {
+		t.Errorf("cached fetch after bypass got %q, want %q", got, "original")
+	}
+}
+
+// Integration test for re-initialising the same alias with a fresh cache
+func TestReinitSameAliasClearsCache(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_REINIT_CACHE_%d", time.Now().UnixNano())
+	setEnv(t, varName, "original")
+	initWithConfig(ctx, t, client, map[string]interface{}{})
+
+	fetchValue := func() string {
+		t.Helper()
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		return resp.Value.Fields["value"].GetStringValue()
+	}
+
+	// Populate the cache, then change the variable
+	if got := fetchValue(); got != "original" {
+		t.Fatalf("initial fetch got %q, want %q", got, "original")
+	}
+	setEnv(t, varName, "updated")
+	if got := fetchValue(); got != "original" {
+		t.Errorf("cached fetch got %q, want %q", got, "original")
+	}
+
+	// Re-Init with the same alias and a different config starts from an empty cache
+	initWithConfig(ctx, t, client, map[string]interface{}{"trim_whitespace": true})
+	if got := fetchValue(); got != "updated" {
+		t.Errorf("fetch after re-init got %q, want %q", got, "updated")
+	}
+}
+
+// Integration test for negative cache entries not surviving a re-Init
+func TestReinitClearsNegativeCache(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_REINIT_NEGATIVE_%d", time.Now().UnixNano())
+	config := map[string]interface{}{"cache_negative": true}
+	initWithConfig(ctx, t, client, config)
+
+	fetchCode := func() codes.Code {
+		t.Helper()
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		return status.Code(err)
+	}
+
+	// Cache the miss, then set the variable
+	if got := fetchCode(); got != codes.NotFound {
+		t.Fatalf("initial fetch code = %v, want %v", got, codes.NotFound)
+	}
+	setEnv(t, varName, "present")
+	if got := fetchCode(); got != codes.NotFound {
+		t.Errorf("cached miss code = %v, want %v", got, codes.NotFound)
+	}
+
+	// Re-Init with the same alias drops the negative entry
+	initWithConfig(ctx, t, client, config)
+	if got := fetchCode(); got != codes.OK {
+		t.Errorf("fetch after re-init code = %v, want %v", got, codes.OK)
+	}
+}
@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Integration test for coercing values to a requested type via request metadata
+func TestFetchCoerceTo(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	numberVar := fmt.Sprintf("TEST_COERCE_NUMBER_%d", suffix)
+	textVar := fmt.Sprintf("TEST_COERCE_TEXT_%d", suffix)
+	refVar := fmt.Sprintf("TEST_COERCE_REF_%d", suffix)
+	setEnv(t, numberVar, "42 ")
+	setEnv(t, textVar, "abc")
+	setEnv(t, refVar, "${"+numberVar+"}")
+	initWithConfig(ctx, t, client, map[string]interface{}{"include_type": true, "expand_references": true})
+
+	coerceCtx := func(target string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, provider.CoerceToMetadataKey, target)
+	}
+
+	t.Run("number with trailing space", func(t *testing.T) {
+		resp, err := client.Fetch(coerceCtx("number"), &pb.FetchRequest{Path: []string{numberVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetNumberValue(); got != 42 {
+			t.Errorf("got %v, want 42", got)
+		}
+		if got := resp.Value.Fields["type"].GetStringValue(); got != "integer" {
+			t.Errorf("got type %q, want integer", got)
+		}
+	})
+
+	t.Run("without coercion the value stays a string", func(t *testing.T) {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{numberVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "42 " {
+			t.Errorf("got %q, want %q", got, "42 ")
+		}
+	})
+
+	t.Run("non-numeric value fails", func(t *testing.T) {
+		_, err := client.Fetch(coerceCtx("number"), &pb.FetchRequest{Path: []string{textVar}})
+		st, _ := status.FromError(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+		if want := fmt.Sprintf("cannot coerce %s to number", textVar); st.Message() != want {
+			t.Errorf("got message %q, want %q", st.Message(), want)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		resp, err := client.Fetch(coerceCtx("string"), &pb.FetchRequest{Path: []string{numberVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "42" {
+			t.Errorf("got %q, want %q", got, "42")
+		}
+	})
+
+	t.Run("expanded reference", func(t *testing.T) {
+		resp, err := client.Fetch(coerceCtx("number"), &pb.FetchRequest{Path: []string{refVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetNumberValue(); got != 42 {
+			t.Errorf("got %v, want 42", got)
+		}
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := client.Fetch(coerceCtx("date"), &pb.FetchRequest{Path: []string{numberVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+}
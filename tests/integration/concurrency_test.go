@@ -0,0 +1,152 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// blockingConverter is a custom converter that holds every fetch inside its
+// fetch slot until released, tracking how many fetches are in flight
+type blockingConverter struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+// register registers the converter under a unique name and returns the name
+func (b *blockingConverter) register(t *testing.T) string {
+	t.Helper()
+	name := fmt.Sprintf("test-blocking-%d", time.Now().UnixNano())
+	b.release = make(chan struct{})
+	converter.RegisterConverter(name, func(string) (interface{}, bool) {
+		current := b.inFlight.Add(1)
+		for {
+			seen := b.maxSeen.Load()
+			if current <= seen || b.maxSeen.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		<-b.release
+		b.inFlight.Add(-1)
+		return nil, false
+	})
+	t.Cleanup(func() { converter.UnregisterConverter(name) })
+	return name
+}
+
+// waitInFlight waits until want fetches are held by the converter
+func (b *blockingConverter) waitInFlight(t *testing.T, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for b.inFlight.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d in-flight fetches, have %d", want, b.inFlight.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Integration test for max_concurrent_fetches in blocking and fail_on_limit modes
+func TestMaxConcurrentFetches(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	varName := fmt.Sprintf("TEST_CONCURRENCY_LIMIT_%d", time.Now().UnixNano())
+	setEnv(t, varName, "value")
+
+	const numRequests = 8
+
+	t.Run("blocks when limit reached", func(t *testing.T) {
+		client, cleanup := startTestServer(t)
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var hook blockingConverter
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"max_concurrent_fetches": 2,
+			"custom_converters":      []interface{}{hook.register(t)},
+		})
+
+		var wg sync.WaitGroup
+		var failed atomic.Int32
+		for i := 0; i < numRequests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}}); err != nil {
+					failed.Add(1)
+				}
+			}()
+		}
+
+		// Both slots are taken; give the queued fetches a chance to overrun the limit
+		hook.waitInFlight(t, 2)
+		time.Sleep(50 * time.Millisecond)
+		if got := hook.inFlight.Load(); got != 2 {
+			t.Errorf("expected 2 fetches in flight while blocked, got %d", got)
+		}
+
+		close(hook.release)
+		wg.Wait()
+		if got := hook.maxSeen.Load(); got > 2 {
+			t.Errorf("in-flight fetches exceeded the limit: max %d", got)
+		}
+		if got := failed.Load(); got != 0 {
+			t.Errorf("expected all %d fetches to succeed, %d failed", numRequests, got)
+		}
+	})
+
+	t.Run("fails when limit reached with fail_on_limit", func(t *testing.T) {
+		client, cleanup := startTestServer(t)
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var hook blockingConverter
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"max_concurrent_fetches": 1,
+			"fail_on_limit":          true,
+			"custom_converters":      []interface{}{hook.register(t)},
+		})
+
+		// Hold the only slot
+		held := make(chan error, 1)
+		go func() {
+			_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+			held <- err
+		}()
+		hook.waitInFlight(t, 1)
+
+		for i := 0; i < numRequests; i++ {
+			_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+			if code := status.Code(err); code != codes.ResourceExhausted {
+				t.Errorf("fetch %d while the slot is held: expected ResourceExhausted, got %v", i, err)
+			}
+		}
+
+		close(hook.release)
+		if err := <-held; err != nil {
+			t.Errorf("fetch holding the slot failed: %v", err)
+		}
+		if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}}); err != nil {
+			t.Errorf("fetch after the slot was released failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,876 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// initWithConfig initializes the provider behind client with the given config map.
+func initWithConfig(ctx context.Context, t *testing.T, client pb.ProviderServiceClient, config map[string]interface{}) {
+	t.Helper()
+
+	configStruct, err := structpb.NewStruct(config)
+	if err != nil {
+		t.Fatalf("failed to create config struct: %v", err)
+	}
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "test-env", Config: configStruct}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+}
+
+// setEnv sets an environment variable for the duration of the test.
+func setEnv(t *testing.T, key, value string) {
+	t.Helper()
+
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("failed to set %s: %v", key, err)
+	}
+	t.Cleanup(func() { _ = os.Unsetenv(key) })
+}
+
+// Integration test for JSON schema validation of JSON values during Fetch
+func TestJSONSchemaValidation(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	timestamp := time.Now().UnixNano()
+	validVar := fmt.Sprintf("TEST_SCHEMA_VALID_%d", timestamp)
+	invalidVar := fmt.Sprintf("TEST_SCHEMA_INVALID_%d", timestamp)
+	scalarVar := fmt.Sprintf("TEST_SCHEMA_SCALAR_%d", timestamp)
+	setEnv(t, validVar, `{"host":"localhost","port":5432}`)
+	setEnv(t, invalidVar, `{"host":"localhost","port":"not-a-port"}`)
+	setEnv(t, scalarVar, "localhost:5432")
+
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"host", "port"},
+		"properties": map[string]interface{}{
+			"host": map[string]interface{}{"type": "string"},
+			"port": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 65535},
+		},
+	}
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"json_schemas": map[string]interface{}{
+			validVar:   schema,
+			invalidVar: schema,
+			scalarVar:  schema,
+		},
+	})
+
+	t.Run("conforming value", func(t *testing.T) {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{validVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		port := resp.Value.Fields["value"].GetStructValue().Fields["port"].GetNumberValue()
+		if port != 5432 {
+			t.Errorf("expected port 5432, got %v", port)
+		}
+	})
+
+	t.Run("non-conforming value", func(t *testing.T) {
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{invalidVar}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		st, _ := status.FromError(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", st.Code())
+		}
+		if !strings.Contains(st.Message(), "$.port") {
+			t.Errorf("expected message to name the failing field, got %q", st.Message())
+		}
+	})
+
+	t.Run("non-JSON value", func(t *testing.T) {
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{scalarVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument || !strings.Contains(st.Message(), "expected type [object]") {
+			t.Errorf("expected InvalidArgument for a string with an object schema, got %v", err)
+		}
+	})
+
+	t.Run("numbers kept as strings", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"json_numbers_as_strings": true,
+			"json_schemas": map[string]interface{}{
+				validVar:   schema,
+				invalidVar: schema,
+			},
+		})
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{validVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if port := resp.Value.Fields["value"].GetStructValue().Fields["port"].GetStringValue(); port != "5432" {
+			t.Errorf("expected port \"5432\", got %q", port)
+		}
+		if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{invalidVar}}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("unsupported keyword fails init", func(t *testing.T) {
+		configStruct, _ := structpb.NewStruct(map[string]interface{}{
+			"json_schemas": map[string]interface{}{
+				validVar: map[string]interface{}{"anyOf": []interface{}{schema}},
+			},
+		})
+		_, err := client.Init(ctx, &pb.InitRequest{Alias: "test-env", Config: configStruct})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument || !strings.Contains(st.Message(), "anyOf") {
+			t.Errorf("expected InvalidArgument naming anyOf, got %v", err)
+		}
+	})
+}
+
+// Integration test for strip_quotes removing surrounding quotes before conversion
+func TestStripQuotesInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	timestamp := time.Now().UnixNano()
+	quotedVar := fmt.Sprintf("TEST_QUOTED_%d", timestamp)
+	plainVar := fmt.Sprintf("TEST_UNQUOTED_%d", timestamp)
+	setEnv(t, quotedVar, `"localhost"`)
+	setEnv(t, plainVar, "localhost")
+
+	for _, stripQuotes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strip_quotes=%v", stripQuotes), func(t *testing.T) {
+			initWithConfig(ctx, t, client, map[string]interface{}{
+				"strip_quotes": stripQuotes,
+			})
+
+			wantQuoted := `"localhost"`
+			if stripQuotes {
+				wantQuoted = "localhost"
+			}
+
+			for varName, want := range map[string]string{quotedVar: wantQuoted, plainVar: "localhost"} {
+				resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+				if err != nil {
+					t.Fatalf("fetch %s failed: %v", varName, err)
+				}
+				if got := resp.Value.Fields["value"].GetStringValue(); got != want {
+					t.Errorf("%s: got %q, want %q", varName, got, want)
+				}
+			}
+		})
+	}
+}
+
+// Integration test for ${VAR} reference expansion in fetched values
+func TestExpandReferencesInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	hostVar := fmt.Sprintf("TEST_EXPAND_HOST_%d", suffix)
+	urlVar := fmt.Sprintf("TEST_EXPAND_URL_%d", suffix)
+	brokenVar := fmt.Sprintf("TEST_EXPAND_BROKEN_%d", suffix)
+	setEnv(t, hostVar, "localhost")
+	setEnv(t, urlVar, fmt.Sprintf("postgres://${%s}:5432/app", hostVar))
+	setEnv(t, brokenVar, "postgres://${TEST_EXPAND_MISSING_VARIABLE}/app")
+
+	t.Run("simple expansion", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"expand_references": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{urlVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "postgres://localhost:5432/app" {
+			t.Errorf("got %q, want %q", got, "postgres://localhost:5432/app")
+		}
+	})
+
+	t.Run("unresolved reference is kept", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"expand_references": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{brokenVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "postgres://${TEST_EXPAND_MISSING_VARIABLE}/app" {
+			t.Errorf("unexpected value %q", got)
+		}
+	})
+
+	t.Run("unresolved reference strict", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"expand_references": true,
+			"strict_expansion":  true,
+		})
+
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{brokenVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("expansion disabled", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{urlVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); !strings.Contains(got, "${") {
+			t.Errorf("expected unexpanded value, got %q", got)
+		}
+	})
+}
+
+// Test that enable_templates renders {{.VAR}} templates before conversion
+func TestTemplatesInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	userVar := fmt.Sprintf("TEST_TEMPLATE_USER_%d", suffix)
+	greetingVar := fmt.Sprintf("TEST_TEMPLATE_GREETING_%d", suffix)
+	brokenVar := fmt.Sprintf("TEST_TEMPLATE_BROKEN_%d", suffix)
+	setEnv(t, userVar, "alice")
+	setEnv(t, greetingVar, fmt.Sprintf("Hello {{.%s}}", userVar))
+	setEnv(t, brokenVar, "Hello {{.TEST_TEMPLATE_MISSING_VARIABLE}}")
+
+	t.Run("simple template", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"enable_templates": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{greetingVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "Hello alice" {
+			t.Errorf("got %q, want %q", got, "Hello alice")
+		}
+	})
+
+	t.Run("missing key renders empty", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"enable_templates": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{brokenVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "Hello " {
+			t.Errorf("got %q, want %q", got, "Hello ")
+		}
+	})
+
+	t.Run("missing key strict", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"enable_templates": true,
+			"strict_templates": true,
+		})
+
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{brokenVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("templates disabled", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{greetingVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); !strings.Contains(got, "{{") {
+			t.Errorf("expected unrendered value, got %q", got)
+		}
+	})
+}
+
+// Integration test for the type field reported with include_type
+func TestIncludeTypeInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	values := map[string]string{
+		"42":            "integer",
+		"3.14":          "float",
+		"true":          "boolean",
+		"hello":         "string",
+		`{"key":"val"}`: "object",
+		`[1,2]`:         "array",
+	}
+	wantTypes := make(map[string]string, len(values))
+	i := 0
+	for value, wantType := range values {
+		varName := fmt.Sprintf("TEST_INCLUDE_TYPE_%d_%d", suffix, i)
+		setEnv(t, varName, value)
+		wantTypes[varName] = wantType
+		i++
+	}
+
+	initWithConfig(ctx, t, client, map[string]interface{}{"include_type": true})
+
+	for varName, wantType := range wantTypes {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", varName, err)
+		}
+		if got := resp.Value.Fields["type"].GetStringValue(); got != wantType {
+			t.Errorf("%s: type got %q, want %q", varName, got, wantType)
+		}
+	}
+
+	// Without the flag the response only carries the value
+	initWithConfig(ctx, t, client, map[string]interface{}{})
+	for varName := range wantTypes {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", varName, err)
+		}
+		if _, ok := resp.Value.Fields["type"]; ok {
+			t.Errorf("%s: unexpected type field without include_type", varName)
+		}
+	}
+}
+
+// Integration test for trim_whitespace normalizing whitespace-only values
+func TestTrimWhitespaceInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_TRIM_WHITESPACE_%d", time.Now().UnixNano())
+	setEnv(t, varName, "   ")
+
+	for _, tt := range []struct {
+		trim bool
+		want string
+	}{{false, "   "}, {true, ""}} {
+		initWithConfig(ctx, t, client, map[string]interface{}{"trim_whitespace": tt.trim})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != tt.want {
+			t.Errorf("trim_whitespace=%v: got %q, want %q", tt.trim, got, tt.want)
+		}
+	}
+}
+
+// Integration test for boolean_output representations
+func TestBooleanOutputInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	trueVar := fmt.Sprintf("TEST_BOOLEAN_OUTPUT_TRUE_%d", suffix)
+	falseVar := fmt.Sprintf("TEST_BOOLEAN_OUTPUT_FALSE_%d", suffix)
+	setEnv(t, trueVar, "true")
+	setEnv(t, falseVar, "no")
+
+	tests := []struct {
+		output    string
+		wantTrue  *structpb.Value
+		wantFalse *structpb.Value
+		wantType  string
+	}{
+		{"bool", structpb.NewBoolValue(true), structpb.NewBoolValue(false), "boolean"},
+		{"numeric", structpb.NewNumberValue(1), structpb.NewNumberValue(0), "integer"},
+		{"string", structpb.NewStringValue("true"), structpb.NewStringValue("false"), "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			initWithConfig(ctx, t, client, map[string]interface{}{"boolean_output": tt.output, "include_type": true})
+
+			for varName, want := range map[string]*structpb.Value{trueVar: tt.wantTrue, falseVar: tt.wantFalse} {
+				resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+				if err != nil {
+					t.Fatalf("fetch %s failed: %v", varName, err)
+				}
+				if got := resp.Value.Fields["value"]; !proto.Equal(got, want) {
+					t.Errorf("%s: got %v, want %v", varName, got, want)
+				}
+				if got := resp.Value.Fields["type"].GetStringValue(); got != tt.wantType {
+					t.Errorf("%s: type got %q, want %q", varName, got, tt.wantType)
+				}
+			}
+		})
+	}
+}
+
+// Integration test for type_name_map remapping reported type names
+func TestTypeNameMapInFetchResponse(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	wantTypes := map[string]string{
+		"true":  "bool",
+		"42":    "int",
+		"hello": "string", // unmapped types pass through
+	}
+	names := make(map[string]string, len(wantTypes))
+	i := 0
+	for value := range wantTypes {
+		varName := fmt.Sprintf("TEST_TYPE_NAME_MAP_%d_%d", suffix, i)
+		setEnv(t, varName, value)
+		names[varName] = value
+		i++
+	}
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"include_type":  true,
+		"type_name_map": map[string]interface{}{"boolean": "bool", "integer": "int"},
+	})
+
+	for varName, value := range names {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", varName, err)
+		}
+		if got := resp.Value.Fields["type"].GetStringValue(); got != wantTypes[value] {
+			t.Errorf("%s (%q): type got %q, want %q", varName, value, got, wantTypes[value])
+		}
+	}
+}
+
+// Integration test for prefix_conversion_overrides selecting conversion flags by prefix
+func TestPrefixConversionOverrides(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	jsonValue := `{"host":"localhost"}`
+	appA := fmt.Sprintf("APPA_SETTINGS_%d", suffix)
+	appB := fmt.Sprintf("APPB_SETTINGS_%d", suffix)
+	other := fmt.Sprintf("APPC_SETTINGS_%d", suffix)
+	setEnv(t, appA, jsonValue)
+	setEnv(t, appB, jsonValue)
+	setEnv(t, other, jsonValue)
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"enable_json_parsing": false,
+		"prefix_conversion_overrides": map[string]interface{}{
+			"APPA_": map[string]interface{}{"enable_json_parsing": true},
+			"APPB_": map[string]interface{}{"enable_json_parsing": false, "enable_type_conversion": false},
+		},
+	})
+
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{appA}})
+	if err != nil {
+		t.Fatalf("fetch %s failed: %v", appA, err)
+	}
+	if host := resp.Value.Fields["value"].GetStructValue().GetFields()["host"].GetStringValue(); host != "localhost" {
+		t.Errorf("APPA_: expected parsed JSON object, got %v", resp.Value.Fields["value"])
+	}
+
+	for _, varName := range []string{appB, other} {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", varName, err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != jsonValue {
+			t.Errorf("%s: expected unparsed string %q, got %v", varName, jsonValue, resp.Value.Fields["value"])
+		}
+	}
+}
+
+// Integration test for strict_conversion rejecting lossy integers
+func TestStrictConversionInFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_STRICT_CONVERSION_%d", time.Now().UnixNano())
+	setEnv(t, varName, "9007199254740993")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{"strict_conversion": true})
+	_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	if !strings.Contains(status.Convert(err).Message(), varName) {
+		t.Errorf("expected error to name the variable, got %q", status.Convert(err).Message())
+	}
+
+	// Without the flag the value is returned as a (lossy) number
+	initWithConfig(ctx, t, client, map[string]interface{}{})
+	if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}}); err != nil {
+		t.Errorf("expected lenient fetch to succeed, got %v", err)
+	}
+}
+
+// Integration test for variable_max_sizes enforcing a per-variable limit
+func TestVariableMaxSizes(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	limited := fmt.Sprintf("TEST_MAX_SIZE_LIMITED_%d", suffix)
+	unlimited := fmt.Sprintf("TEST_MAX_SIZE_UNLIMITED_%d", suffix)
+	value := strings.Repeat("x", 32) // well under the global 1MB limit
+	setEnv(t, limited, value)
+	setEnv(t, unlimited, value)
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"variable_max_sizes": map[string]interface{}{limited: 16},
+	})
+
+	_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{limited}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for %s, got %v", limited, err)
+	}
+	if !strings.Contains(status.Convert(err).Message(), limited) {
+		t.Errorf("expected error to name the variable, got %q", status.Convert(err).Message())
+	}
+
+	// Variables without a specific limit fall back to the global limit
+	if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{unlimited}}); err != nil {
+		t.Errorf("expected fetch of %s to succeed, got %v", unlimited, err)
+	}
+}
+
+// Integration test ensuring conversion errors never echo the raw value
+func TestConversionErrorRedactsValue(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const secret = "sk_live_REDACTME42"
+	varName := fmt.Sprintf("TEST_REDACTED_JSON_%d", time.Now().UnixNano())
+	setEnv(t, varName, `{"token": `+secret+`}`)
+
+	initWithConfig(ctx, t, client, map[string]interface{}{"enable_json_parsing": true})
+	_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	msg := status.Convert(err).Message()
+	if strings.Contains(msg, secret) || strings.Contains(msg, "sk_") || strings.Contains(msg, "'s'") {
+		t.Errorf("error message leaks the value: %q", msg)
+	}
+	if !strings.Contains(msg, varName) || !strings.Contains(msg, "invalid JSON") {
+		t.Errorf("expected variable name and error class in message, got %q", msg)
+	}
+}
+
+// Integration test for emit_conversion_warnings reporting ambiguous conversions
+func TestConversionWarningsInFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	tests := []struct {
+		varName string
+		value   string
+		want    string
+	}{
+		{varName: "ONE", value: "1", want: "could be boolean"},
+		{varName: "YES", value: "yes", want: "could be string"},
+		{varName: "PLAIN", value: "hello"},
+		// Warnings describe the expanded value
+		{varName: "REF", value: "${TEST_CONVERSION_WARNINGS_ONE_%d}", want: "could be boolean"},
+		{varName: "REF_PLAIN", value: "${TEST_CONVERSION_WARNINGS_PLAIN_%d}"},
+	}
+	for i := range tests {
+		tests[i].varName = fmt.Sprintf("TEST_CONVERSION_WARNINGS_%s_%d", tests[i].varName, suffix)
+		if strings.Contains(tests[i].value, "%d") {
+			tests[i].value = fmt.Sprintf(tests[i].value, suffix)
+		}
+		setEnv(t, tests[i].varName, tests[i].value)
+	}
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"emit_conversion_warnings": true,
+		"expand_references":        true,
+	})
+
+	for _, tt := range tests {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{tt.varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", tt.varName, err)
+		}
+		field, ok := resp.Value.Fields["warnings"]
+		if !ok {
+			t.Fatalf("%s: expected warnings field", tt.value)
+		}
+		warnings := field.GetListValue().GetValues()
+		if tt.want == "" {
+			if len(warnings) != 0 {
+				t.Errorf("%s: expected no warnings, got %v", tt.value, warnings)
+			}
+			continue
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0].GetStringValue(), tt.want) {
+			t.Errorf("%s: expected warning containing %q, got %v", tt.value, tt.want, warnings)
+		}
+	}
+}
+
+// Integration test for max_indirection_depth bounding reference cycles
+func TestMaxIndirectionDepth(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	first := fmt.Sprintf("TEST_INDIRECTION_A_%d", suffix)
+	second := fmt.Sprintf("TEST_INDIRECTION_B_%d", suffix)
+	chain := fmt.Sprintf("TEST_INDIRECTION_CHAIN_%d", suffix)
+	setEnv(t, first, fmt.Sprintf("${%s}", second))
+	setEnv(t, second, fmt.Sprintf("${%s}", first))
+	setEnv(t, chain, fmt.Sprintf("${%s}", second+"_END"))
+	setEnv(t, second+"_END", "end")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"expand_references":     true,
+		"max_indirection_depth": 3,
+	})
+
+	// A reference cycle stops at the configured depth with a clear error
+	_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{first}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for cycle, got %v", err)
+	}
+	if msg := status.Convert(err).Message(); !strings.Contains(msg, "maximum depth (3)") {
+		t.Errorf("expected depth error, got %q", msg)
+	}
+
+	// Chains within the depth still resolve
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{chain}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "end" {
+		t.Errorf("expected %q, got %q", "end", got)
+	}
+}
+
+// Integration test for bool_numeric_variables converting 0/1 to booleans
+func TestBoolNumericVariables(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	listed := fmt.Sprintf("TEST_BOOL_NUMERIC_LISTED_%d", suffix)
+	listedOff := fmt.Sprintf("TEST_BOOL_NUMERIC_LISTED_OFF_%d", suffix)
+	unlisted := fmt.Sprintf("TEST_BOOL_NUMERIC_UNLISTED_%d", suffix)
+	setEnv(t, listed, "1")
+	setEnv(t, listedOff, "0")
+	setEnv(t, unlisted, "1")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"bool_numeric_variables": []interface{}{listed, listedOff},
+		"conversion_cache_size":  16,
+	})
+
+	tests := []struct {
+		varName string
+		want    *structpb.Value
+	}{
+		{listed, structpb.NewBoolValue(true)},
+		{listedOff, structpb.NewBoolValue(false)},
+		{unlisted, structpb.NewNumberValue(1)},
+	}
+	for _, tt := range tests {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{tt.varName}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", tt.varName, err)
+		}
+		if got := resp.Value.Fields["value"]; !proto.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.varName, got, tt.want)
+		}
+	}
+}
+
+// Test that NOMOS_DISABLE_CONVERSION switches conversion off at Fetch time without re-Init
+func TestDisableConversionEnvVar(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("TEST_DISABLE_CONVERSION_%d", time.Now().UnixNano())
+	setEnv(t, varName, "42")
+	initWithConfig(ctx, t, client, map[string]interface{}{"include_type": true})
+
+	fetchType := func() string {
+		t.Helper()
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		return resp.Value.Fields["type"].GetStringValue()
+	}
+
+	if got := fetchType(); got != "integer" {
+		t.Fatalf("expected integer before disabling conversion, got %q", got)
+	}
+
+	setEnv(t, provider.DisableConversionEnvVar, "true")
+	if got := fetchType(); got != "string" {
+		t.Errorf("expected string with conversion disabled, got %q", got)
+	}
+
+	setEnv(t, provider.DisableConversionEnvVar, "false")
+	if got := fetchType(); got != "integer" {
+		t.Errorf("expected integer after re-enabling conversion, got %q", got)
+	}
+}
+
+// Test that values matching null_tokens are treated as missing
+func TestNullTokensTreatedAsMissing(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	noneVar := fmt.Sprintf("TEST_NULL_TOKEN_NONE_%d", suffix)
+	valueVar := fmt.Sprintf("TEST_NULL_TOKEN_VALUE_%d", suffix)
+	refVar := fmt.Sprintf("TEST_NULL_TOKEN_REF_%d", suffix)
+	setEnv(t, noneVar, " None ")
+	setEnv(t, valueVar, "nonexistent")
+	setEnv(t, refVar, fmt.Sprintf("host=${%s}", noneVar))
+
+	t.Run("token is NotFound", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"null_tokens": []interface{}{"null", "none", "nil"}})
+
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{noneVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.NotFound {
+			t.Errorf("expected NotFound, got %v", err)
+		}
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{valueVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != "nonexistent" {
+			t.Errorf("got %q, want %q", got, "nonexistent")
+		}
+	})
+
+	t.Run("token is an unresolved reference", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"null_tokens":       []interface{}{"none"},
+			"expand_references": true,
+			"strict_expansion":  true,
+		})
+
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{refVar}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("tokens not configured", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{noneVar}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != " None " {
+			t.Errorf("got %q, want %q", got, " None ")
+		}
+	})
+}
+
+// Test that case_transform governs variable names only and never JSON object keys
+func TestJSONKeysKeepCasing(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("JSONKEYS%d_", time.Now().UnixNano())
+	setEnv(t, prefix+"APP_SETTINGS", `{"maxRetries": 3, "Nested": {"apiURL": "http://localhost", "lower_key": true}}`)
+
+	for _, transform := range []string{"upper", "screaming"} {
+		t.Run(transform, func(t *testing.T) {
+			initWithConfig(ctx, t, client, map[string]interface{}{
+				"prefix":              prefix,
+				"case_transform":      transform,
+				"enable_json_parsing": true,
+			})
+
+			resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"app", "settings"}})
+			if err != nil {
+				t.Fatalf("fetch failed: %v", err)
+			}
+			value := resp.Value.Fields["value"].GetStructValue()
+			if value == nil {
+				t.Fatalf("expected an object value, got %v", resp.Value.Fields["value"])
+			}
+			if _, ok := value.Fields["maxRetries"]; !ok {
+				t.Errorf("expected key %q to keep its casing, got keys %v", "maxRetries", value.AsMap())
+			}
+			nested := value.Fields["Nested"].GetStructValue()
+			if nested == nil {
+				t.Fatalf("expected key %q to keep its casing, got keys %v", "Nested", value.AsMap())
+			}
+			for _, key := range []string{"apiURL", "lower_key"} {
+				if _, ok := nested.Fields[key]; !ok {
+					t.Errorf("expected nested key %q to keep its casing, got %v", key, nested.AsMap())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Integration test for group_indexed assembling numbered variables into a struct
+func TestGroupIndexedFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	group := fmt.Sprintf("TESTSERVERS%d", time.Now().UnixNano())
+	for i := 1; i <= 3; i++ {
+		setEnv(t, fmt.Sprintf("%s_%d_HOST", group, i), fmt.Sprintf("host%d.example.com", i))
+		setEnv(t, fmt.Sprintf("%s_%d_PORT", group, i), fmt.Sprintf("%d", 8080+i))
+	}
+	// Non-indexed variables sharing the group prefix are ignored
+	setEnv(t, group+"_COUNT", "3")
+
+	t.Run("grouped response shape", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"group_indexed": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{group}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+
+		servers := resp.Value.Fields["value"].GetStructValue()
+		if servers == nil {
+			t.Fatalf("expected struct value, got %v", resp.Value.Fields["value"])
+		}
+		if len(servers.Fields) != 3 {
+			t.Fatalf("expected 3 indexed entries, got %d: %v", len(servers.Fields), servers.AsMap())
+		}
+		for i := 1; i <= 3; i++ {
+			entry := servers.Fields[fmt.Sprintf("%d", i)].GetStructValue()
+			if entry == nil {
+				t.Fatalf("missing entry %d", i)
+			}
+			if got, want := entry.Fields["host"].GetStringValue(), fmt.Sprintf("host%d.example.com", i); got != want {
+				t.Errorf("entry %d host: got %q, want %q", i, got, want)
+			}
+			if got, want := entry.Fields["port"].GetNumberValue(), float64(8080+i); got != want {
+				t.Errorf("entry %d port: got %v, want %v", i, got, want)
+			}
+		}
+	})
+
+	t.Run("path resolved through case transform", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{"group_indexed": true})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{strings.ToLower(group)}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := len(resp.Value.Fields["value"].GetStructValue().GetFields()); got != 3 {
+			t.Errorf("expected 3 indexed entries, got %d", got)
+		}
+	})
+
+	t.Run("path split on join separator", func(t *testing.T) {
+		nested := fmt.Sprintf("TESTNESTED%d", time.Now().UnixNano())
+		setEnv(t, nested+"_DB_1_HOST", "db1.example.com")
+		setEnv(t, nested+"_DB_2_HOST", "db2.example.com")
+
+		initWithConfig(ctx, t, client, map[string]interface{}{"group_indexed": true, "join_separator": "."})
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{strings.ToLower(nested) + ".db"}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		entry := resp.Value.Fields["value"].GetStructValue().GetFields()["2"].GetStructValue()
+		if got := entry.GetFields()["host"].GetStringValue(); got != "db2.example.com" {
+			t.Errorf("entry 2 host: got %q, want %q", got, "db2.example.com")
+		}
+	})
+
+	t.Run("members pass per-variable checks", func(t *testing.T) {
+		checked := fmt.Sprintf("TESTCHECKED%d", time.Now().UnixNano())
+		setEnv(t, checked+"_1", "primary")
+		setEnv(t, checked+"_2", "none")
+		setEnv(t, checked+"_3", "a value longer than the limit")
+
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"group_indexed": true,
+			"null_tokens":   []interface{}{"none"},
+		})
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{checked}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		members := resp.Value.Fields["value"].GetStructValue().GetFields()
+		if _, ok := members["2"]; ok || len(members) != 2 {
+			t.Errorf("expected the null token member to be skipped, got %v", members)
+		}
+
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"group_indexed":      true,
+			"variable_max_sizes": map[string]interface{}{checked + "_3": 8},
+		})
+		_, err = client.Fetch(ctx, &pb.FetchRequest{Path: []string{checked}})
+		if st, _ := status.FromError(err); st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for an oversized member, got %v", err)
+		}
+	})
+
+	t.Run("secrets are not enumerated", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"group_indexed":    true,
+			"secret_variables": []interface{}{group + "_2_HOST", group + "_2_PORT"},
+		})
+
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{group}})
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		servers := resp.Value.Fields["value"].GetStructValue().GetFields()
+		if _, ok := servers["2"]; ok || len(servers) != 2 {
+			t.Errorf("expected secret members to be skipped, got %v", servers)
+		}
+	})
+
+	t.Run("disabled returns not found", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{})
+
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{group}})
+		if st, _ := status.FromError(err); st.Code() != codes.NotFound {
+			t.Errorf("expected NotFound, got %v", err)
+		}
+	})
+}
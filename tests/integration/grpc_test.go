@@ -1459,3 +1459,78 @@ func TestTypeConversionErrorHandling(t *testing.T) {
 		t.Errorf("expected InvalidArgument or Internal error, got %v", st.Code())
 	}
 }
+
+// Integration test for required_missing_as_precondition flagging required
+// variables removed after Init
+func TestRequiredVariableUnsetAfterInit(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	required := fmt.Sprintf("TEST_REQUIRED_UNSET_%d", suffix)
+	optional := fmt.Sprintf("TEST_OPTIONAL_UNSET_%d", suffix)
+	setEnv(t, required, "value")
+	setEnv(t, optional, "value")
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		varName  string
+		wantCode codes.Code
+	}{
+		{"required variable with mode enabled", true, required, codes.FailedPrecondition},
+		{"optional variable with mode enabled", true, optional, codes.NotFound},
+		{"required variable with mode disabled", false, required, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, required, "value")
+			setEnv(t, optional, "value")
+			initWithConfig(ctx, t, client, map[string]interface{}{
+				"required_variables":               []interface{}{required},
+				"required_missing_as_precondition": tt.enabled,
+			})
+
+			if err := os.Unsetenv(tt.varName); err != nil {
+				t.Fatalf("failed to unset %s: %v", tt.varName, err)
+			}
+
+			_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{tt.varName}})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("expected %v, got %v (%v)", tt.wantCode, got, err)
+			}
+		})
+	}
+}
+
+// Integration test for type_override changing the type reported by Info
+func TestInfoTypeOverride(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	initWithConfig(ctx, t, client, map[string]interface{}{"type_override": "tenant-a-env"})
+	resp, err := client.Info(ctx, &pb.InfoRequest{})
+	if err != nil {
+		t.Fatalf("info failed: %v", err)
+	}
+	if resp.Type != "tenant-a-env" {
+		t.Errorf("expected type %q, got %q", "tenant-a-env", resp.Type)
+	}
+
+	// Re-initializing without the override restores the default
+	initWithConfig(ctx, t, client, map[string]interface{}{})
+	resp, err = client.Info(ctx, &pb.InfoRequest{})
+	if err != nil {
+		t.Fatalf("info failed: %v", err)
+	}
+	if resp.Type != "environment-variables" {
+		t.Errorf("expected default type, got %q", resp.Type)
+	}
+}
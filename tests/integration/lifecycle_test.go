@@ -5,11 +5,15 @@ package integration
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
 	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
 )
 
@@ -65,3 +69,119 @@ func TestHealthTransitions(t *testing.T) {
 		t.Errorf("expected STATUS_DEGRADED after shutdown, got %v", resp.Status)
 	}
 }
+
+// Integration test for machine-readable Health reasons across lifecycle states
+func TestHealthReasons(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	healthReason := func() provider.HealthReason {
+		t.Helper()
+		resp, err := client.Health(ctx, &pb.HealthRequest{})
+		if err != nil {
+			t.Fatalf("health check failed: %v", err)
+		}
+		return provider.ParseHealthReason(resp.Message)
+	}
+
+	if got := healthReason(); got != provider.HealthReasonNotInitialized {
+		t.Errorf("before init: got reason %q, want %q", got, provider.HealthReasonNotInitialized)
+	}
+
+	// Failed Init due to a missing required variable
+	configStruct, _ := structpb.NewStruct(map[string]interface{}{
+		"required_variables": []interface{}{"TEST_HEALTH_REASON_MISSING_VARIABLE"},
+	})
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "test-env", Config: configStruct}); err == nil {
+		t.Fatal("expected init to fail with missing required variable")
+	}
+	if got := healthReason(); got != provider.HealthReasonRequiredMissing {
+		t.Errorf("after failed init: got reason %q, want %q", got, provider.HealthReasonRequiredMissing)
+	}
+
+	// Successful Init
+	configStruct, _ = structpb.NewStruct(map[string]interface{}{})
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "test-env", Config: configStruct}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if got := healthReason(); got != provider.HealthReasonReady {
+		t.Errorf("after init: got reason %q, want %q", got, provider.HealthReasonReady)
+	}
+
+	// Shutdown
+	if _, err := client.Shutdown(ctx, &pb.ShutdownRequest{}); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	if got := healthReason(); got != provider.HealthReasonStopped {
+		t.Errorf("after shutdown: got reason %q, want %q", got, provider.HealthReasonStopped)
+	}
+}
+
+// Integration test for the cache entry count reported with health_include_cache_stats
+func TestHealthIncludeCacheStats(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	first := fmt.Sprintf("TEST_HEALTH_CACHE_FIRST_%d", suffix)
+	second := fmt.Sprintf("TEST_HEALTH_CACHE_SECOND_%d", suffix)
+	t.Setenv(first, "one")
+	t.Setenv(second, "two")
+
+	healthMessage := func() string {
+		t.Helper()
+		resp, err := client.Health(ctx, &pb.HealthRequest{})
+		if err != nil {
+			t.Fatalf("health check failed: %v", err)
+		}
+		return resp.Message
+	}
+	cacheEntries := func() int {
+		t.Helper()
+		message := healthMessage()
+		_, count, found := strings.Cut(message, "(cache entries: ")
+		if !found {
+			t.Fatalf("expected cache entries in health message, got %q", message)
+		}
+		entries, err := strconv.Atoi(strings.TrimSuffix(count, ")"))
+		if err != nil {
+			t.Fatalf("invalid cache entry count in %q: %v", message, err)
+		}
+		return entries
+	}
+
+	// Default response carries no cache stats
+	configStruct, _ := structpb.NewStruct(map[string]interface{}{})
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "test-env", Config: configStruct}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if got := healthMessage(); got != "ready: provider is ready" {
+		t.Errorf("expected default health message, got %q", got)
+	}
+
+	configStruct, _ = structpb.NewStruct(map[string]interface{}{"health_include_cache_stats": true})
+	if _, err := client.Init(ctx, &pb.InitRequest{Alias: "test-health-cache", Config: configStruct}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if got := cacheEntries(); got != 0 {
+		t.Errorf("expected an empty cache after init, got %d entries", got)
+	}
+
+	for i, name := range []string{first, second} {
+		if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{name}}); err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := cacheEntries(); got != i+1 {
+			t.Errorf("after %d fetches: got %d cache entries, want %d", i+1, got, i+1)
+		}
+	}
+	if provider.ParseHealthReason(healthMessage()) != provider.HealthReasonReady {
+		t.Errorf("expected ready reason with cache stats, got %q", healthMessage())
+	}
+}
@@ -0,0 +1,458 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Integration test for raw: paths bypassing path transformation
+func TestRawVariableNameFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	varName := fmt.Sprintf("Raw_Mixed_Case_%d", time.Now().UnixNano())
+	setEnv(t, varName, "raw-value")
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		path     []string
+		wantCode codes.Code
+	}{
+		{
+			name: "ignores case_transform and prepend prefix",
+			config: map[string]interface{}{
+				"case_transform": "lower",
+				"prefix":         "MYAPP_",
+				"prefix_mode":    "prepend",
+			},
+			path:     []string{"raw:" + varName},
+			wantCode: codes.OK,
+		},
+		{
+			name: "still honors filter_only prefix",
+			config: map[string]interface{}{
+				"prefix":      "MYAPP_",
+				"prefix_mode": "filter_only",
+			},
+			path:     []string{"raw:" + varName},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "empty raw name",
+			config:   map[string]interface{}{},
+			path:     []string{"raw:"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initWithConfig(ctx, t, client, tt.config)
+
+			resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: tt.path})
+			if st, _ := status.FromError(err); st.Code() != tt.wantCode {
+				t.Fatalf("expected %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				return
+			}
+			if got := resp.Value.Fields["value"].GetStringValue(); got != "raw-value" {
+				t.Errorf("got %q, want %q", got, "raw-value")
+			}
+		})
+	}
+}
+
+// Integration test for include_resolved_name across prefix modes
+func TestIncludeResolvedName(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	prefix := fmt.Sprintf("RESOLVED%d_", suffix)
+	setEnv(t, prefix+"DATABASE_HOST", "prefixed")
+	setEnv(t, fmt.Sprintf("RESOLVED_%d_HOST", suffix), "plain")
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		path   []string
+		want   string
+	}{
+		{
+			name:   "direct",
+			config: map[string]interface{}{"include_resolved_name": true},
+			path:   []string{fmt.Sprintf("RESOLVED_%d_HOST", suffix)},
+			want:   fmt.Sprintf("RESOLVED_%d_HOST", suffix),
+		},
+		{
+			name:   "transformed",
+			config: map[string]interface{}{"include_resolved_name": true},
+			path:   []string{"resolved", fmt.Sprint(suffix), "host"},
+			want:   fmt.Sprintf("RESOLVED_%d_HOST", suffix),
+		},
+		{
+			name:   "prepend",
+			config: map[string]interface{}{"include_resolved_name": true, "prefix": prefix, "prefix_mode": "prepend"},
+			path:   []string{"database", "host"},
+			want:   prefix + "DATABASE_HOST",
+		},
+		{
+			name:   "filter_only",
+			config: map[string]interface{}{"include_resolved_name": true, "prefix": prefix, "prefix_mode": "filter_only"},
+			path:   []string{prefix + "DATABASE_HOST"},
+			want:   prefix + "DATABASE_HOST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initWithConfig(ctx, t, client, tt.config)
+
+			resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: tt.path})
+			if err != nil {
+				t.Fatalf("fetch %v failed: %v", tt.path, err)
+			}
+			if got := resp.Value.Fields["resolved_name"].GetStringValue(); got != tt.want {
+				t.Errorf("resolved_name got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Without the flag the resolved name is not exposed
+	initWithConfig(ctx, t, client, map[string]interface{}{"prefix": prefix})
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"database", "host"}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if _, ok := resp.Value.Fields["resolved_name"]; ok {
+		t.Error("unexpected resolved_name field without include_resolved_name")
+	}
+}
+
+// Integration test for passthrough_unfiltered making the filter_only prefix advisory
+func TestPassthroughUnfiltered(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	prefixed := fmt.Sprintf("PASSAPP_HOST_%d", suffix)
+	unprefixed := fmt.Sprintf("OTHERAPP_HOST_%d", suffix)
+	setEnv(t, prefixed, "prefixed")
+	setEnv(t, unprefixed, "unprefixed")
+
+	config := map[string]interface{}{"prefix": "PASSAPP_", "prefix_mode": "filter_only"}
+
+	// Without passthrough, non-prefixed variables are filtered
+	initWithConfig(ctx, t, client, config)
+	_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{unprefixed}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound without passthrough, got %v", err)
+	}
+
+	config["passthrough_unfiltered"] = true
+	initWithConfig(ctx, t, client, config)
+
+	for name, want := range map[string]string{prefixed: "prefixed", unprefixed: "unprefixed"} {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{name}})
+		if err != nil {
+			t.Fatalf("fetch %s failed: %v", name, err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	// Multi-segment paths resolve without the prefix as well
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"otherapp", "host", fmt.Sprint(suffix)}})
+	if err != nil {
+		t.Fatalf("multi-segment fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "unprefixed" {
+		t.Errorf("multi-segment: got %q, want %q", got, "unprefixed")
+	}
+}
+
+// Integration test for include_present distinguishing absent, empty, and set variables
+func TestIncludePresent(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	emptyVar := fmt.Sprintf("TEST_PRESENT_EMPTY_%d", suffix)
+	setVar := fmt.Sprintf("TEST_PRESENT_SET_%d", suffix)
+	absentVar := fmt.Sprintf("TEST_PRESENT_ABSENT_%d", suffix)
+	group := fmt.Sprintf("TESTPRESENTGROUP%d", suffix)
+	setEnv(t, emptyVar, "")
+	setEnv(t, setVar, "value")
+	setEnv(t, group+"_1", "first")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{"include_present": true, "group_indexed": true})
+
+	tests := []struct {
+		name        string
+		varName     string
+		wantPresent bool
+	}{
+		{"present with value", setVar, true},
+		{"present and empty", emptyVar, true},
+		{"assembled from indexed variables", group, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{tt.varName}})
+			if err != nil {
+				t.Fatalf("fetch failed: %v", err)
+			}
+			present, ok := resp.Value.Fields["present"]
+			if !ok {
+				t.Fatal("response missing 'present' field")
+			}
+			if present.GetBoolValue() != tt.wantPresent {
+				t.Errorf("present got %v, want %v", present.GetBoolValue(), tt.wantPresent)
+			}
+		})
+	}
+
+	t.Run("absent", func(t *testing.T) {
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{absentVar}})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("expected NotFound for absent variable, got %v", err)
+		}
+	})
+}
+
+// Integration test for declared_paths validated at Init
+func TestDeclaredPaths(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	suffix := time.Now().UnixNano()
+	prefix := fmt.Sprintf("DECLARED%d_", suffix)
+	setEnv(t, prefix+"DATABASE_HOST", "db.local")
+	setEnv(t, prefix+"API_KEY", "secret")
+
+	tests := []struct {
+		name     string
+		paths    []interface{}
+		wantCode codes.Code
+	}{
+		{
+			name:     "all declared paths satisfied",
+			paths:    []interface{}{[]interface{}{"database", "host"}, []interface{}{"api", "key"}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "declared path missing",
+			paths:    []interface{}{[]interface{}{"database", "host"}, []interface{}{"database", "port"}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := structpb.NewStruct(map[string]interface{}{
+				"prefix":                  prefix,
+				"declared_paths":          tt.paths,
+				"declared_paths_required": true,
+			})
+			if err != nil {
+				t.Fatalf("failed to create config: %v", err)
+			}
+			alias := "test-declared-" + strings.ReplaceAll(tt.name, " ", "-")
+			_, err = client.Init(ctx, &pb.InitRequest{Alias: alias, Config: config})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("expected %v, got %v (%v)", tt.wantCode, got, err)
+			}
+			if tt.wantCode == codes.InvalidArgument && !strings.Contains(status.Convert(err).Message(), prefix+"DATABASE_PORT") {
+				t.Errorf("expected error to name the missing variable, got %q", status.Convert(err).Message())
+			}
+		})
+	}
+
+	// The rejected Init must not have replaced the previous configuration
+	info, err := client.Info(ctx, &pb.InfoRequest{})
+	if err != nil {
+		t.Fatalf("info failed: %v", err)
+	}
+	if want := "test-declared-all-declared-paths-satisfied"; info.Alias != want {
+		t.Errorf("alias after rejected Init = %q, want %q", info.Alias, want)
+	}
+}
+
+// Integration test for camel_split applied to fetched paths
+func TestCamelSplitFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("CAMEL%d_", time.Now().UnixNano())
+	setEnv(t, prefix+"SERVICE_API_KEY", "secret")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"prefix":      prefix,
+		"camel_split": true,
+	})
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"service", "apiKey"}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "secret" {
+		t.Errorf("expected %q, got %q", "secret", got)
+	}
+}
+
+// Integration test for drop_empty_segments in Fetch
+func TestDropEmptySegmentsFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("DROPEMPTY%d_", time.Now().UnixNano())
+	setEnv(t, prefix+"APP_HOST", "localhost")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"prefix":              prefix,
+		"drop_empty_segments": true,
+	})
+	resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"app", "", "host"}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "localhost" {
+		t.Errorf("expected %q, got %q", "localhost", got)
+	}
+
+	if _, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{"", " "}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for all-empty path, got %v", err)
+	}
+}
+
+// Integration test for prefix_separators with two prefixes using different separators
+func TestPrefixSeparatorsFetch(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("SEPARATED%d", time.Now().UnixNano())
+	underscorePrefix := prefix + "UNDERSCORE"
+	dashPrefix := prefix + "DASH"
+	setEnv(t, underscorePrefix+"_DATABASE_HOST", "underscore-host")
+	setEnv(t, dashPrefix+"-DATABASE-HOST", "dash-host")
+
+	initWithConfig(ctx, t, client, map[string]interface{}{
+		"prefix":      prefix,
+		"prefix_mode": "filter_only",
+		"prefix_separators": map[string]interface{}{
+			underscorePrefix: "_",
+			dashPrefix:       "-",
+		},
+	})
+
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{underscorePrefix, "underscore-host"},
+		{dashPrefix, "dash-host"},
+	}
+	for _, tt := range tests {
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{strings.ToLower(tt.prefix), "database", "host"}})
+		if err != nil {
+			t.Fatalf("fetch under %s failed: %v", tt.prefix, err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != tt.want {
+			t.Errorf("under %s: expected %q, got %q", tt.prefix, tt.want, got)
+		}
+	}
+}
+
+// Integration test for injected_variables chained with the process environment
+func TestInjectedVariables(t *testing.T) {
+	client, cleanup := startTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	timestamp := time.Now().UnixNano()
+	shared := fmt.Sprintf("TEST_INJECTED_SHARED_%d", timestamp)
+	injectedOnly := fmt.Sprintf("TEST_INJECTED_ONLY_%d", timestamp)
+	setEnv(t, shared, "from-process")
+	injected := map[string]interface{}{shared: "from-config", injectedOnly: "injected"}
+
+	fetchValue := func(name string) string {
+		t.Helper()
+		resp, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{name}})
+		if err != nil {
+			t.Fatalf("fetch of %s failed: %v", name, err)
+		}
+		return resp.Value.Fields["value"].GetStringValue()
+	}
+
+	t.Run("process environment wins by default", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"injected_variables": injected,
+			"required_variables": []interface{}{injectedOnly},
+		})
+		if got := fetchValue(shared); got != "from-process" {
+			t.Errorf("shared variable: got %q, want %q", got, "from-process")
+		}
+		if got := fetchValue(injectedOnly); got != "injected" {
+			t.Errorf("injected variable: got %q, want %q", got, "injected")
+		}
+	})
+
+	t.Run("override puts injected values first", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{
+			"injected_variables":          injected,
+			"injected_variables_override": true,
+		})
+		if got := fetchValue(shared); got != "from-config" {
+			t.Errorf("shared variable: got %q, want %q", got, "from-config")
+		}
+	})
+
+	t.Run("injected variables are dropped on re-init", func(t *testing.T) {
+		initWithConfig(ctx, t, client, map[string]interface{}{})
+		_, err := client.Fetch(ctx, &pb.FetchRequest{Path: []string{injectedOnly}})
+		if st, _ := status.FromError(err); st.Code() != codes.NotFound {
+			t.Errorf("expected NotFound after re-init without injected_variables, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+//go:build !windows
+// +build !windows
+
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test binary_encoding for values that are not valid UTF-8.
+// Windows stores environment values as UTF-16, so arbitrary bytes are Unix-only.
+func TestBinaryEncoding(t *testing.T) {
+	varName := fmt.Sprintf("TEST_BINARY_VALUE_%d", time.Now().UnixNano())
+	t.Setenv(varName, "\xff\xfeA\x80")
+
+	tests := []struct {
+		encoding  string // empty uses the default
+		want      string
+		wantError codes.Code
+	}{
+		{encoding: "base64", want: "//5BgA=="},
+		{encoding: "hex", want: "fffe4180"},
+		{encoding: "error", wantError: codes.InvalidArgument},
+		{encoding: "", wantError: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run("encoding="+tt.encoding, func(t *testing.T) {
+			config := map[string]interface{}{"include_type": true}
+			if tt.encoding != "" {
+				config["binary_encoding"] = tt.encoding
+			}
+			configStruct, err := structpb.NewStruct(config)
+			if err != nil {
+				t.Fatalf("failed to create config struct: %v", err)
+			}
+
+			prov := provider.New(logger.New(logger.ERROR))
+			if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "test-provider", Config: configStruct}); err != nil {
+				t.Fatalf("init failed: %v", err)
+			}
+
+			resp, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{varName}})
+			if tt.wantError != codes.OK {
+				if status.Code(err) != tt.wantError {
+					t.Fatalf("expected %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetch failed: %v", err)
+			}
+			if got := resp.Value.Fields["value"].GetStringValue(); got != tt.want {
+				t.Errorf("value got %q, want %q", got, tt.want)
+			}
+			if got := resp.Value.Fields["type"].GetStringValue(); got != "binary" {
+				t.Errorf("type got %q, want %q", got, "binary")
+			}
+		})
+	}
+}
+
+// Test that coercion applies binary_encoding to values that are not valid UTF-8
+func TestBinaryCoerce(t *testing.T) {
+	varName := fmt.Sprintf("TEST_BINARY_COERCE_%d", time.Now().UnixNano())
+	t.Setenv(varName, "\xff\xfeA\x80")
+
+	tests := []struct {
+		target    string
+		encoding  string // empty uses the default
+		want      string
+		wantError codes.Code
+	}{
+		{target: "string", encoding: "base64", want: "//5BgA=="},
+		{target: "string", wantError: codes.InvalidArgument},
+		{target: "number", encoding: "base64", wantError: codes.InvalidArgument},
+		{target: "boolean", wantError: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+"/encoding="+tt.encoding, func(t *testing.T) {
+			config := map[string]interface{}{}
+			if tt.encoding != "" {
+				config["binary_encoding"] = tt.encoding
+			}
+			configStruct, err := structpb.NewStruct(config)
+			if err != nil {
+				t.Fatalf("failed to create config struct: %v", err)
+			}
+
+			prov := provider.New(logger.New(logger.ERROR))
+			if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "test-provider", Config: configStruct}); err != nil {
+				t.Fatalf("init failed: %v", err)
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(provider.CoerceToMetadataKey, tt.target))
+			resp, err := prov.Fetch(ctx, &pb.FetchRequest{Path: []string{varName}})
+			if tt.wantError != codes.OK {
+				if status.Code(err) != tt.wantError {
+					t.Fatalf("expected %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetch failed: %v", err)
+			}
+			if got := resp.Value.Fields["value"].GetStringValue(); got != tt.want {
+				t.Errorf("value got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that reject_control_chars fails fetches of values with control characters
+func TestRejectControlChars(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		enabled  bool
+		wantCode codes.Code
+	}{
+		{"control byte rejected", "abc\x01def", true, codes.InvalidArgument},
+		{"delete rejected", "abc\x7f", true, codes.InvalidArgument},
+		{"clean value", "clean value", true, codes.OK},
+		{"common whitespace allowed", "line one\n\tline two\r\n", true, codes.OK},
+		{"disabled", "abc\x01def", false, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONTROL_CHARS_TEST_VALUE", tt.value)
+
+			prov := provider.New(logger.NewWithOutput(logger.ERROR, &bytes.Buffer{}))
+			cfg, err := structpb.NewStruct(map[string]interface{}{"reject_control_chars": tt.enabled})
+			if err != nil {
+				t.Fatalf("failed to create config: %v", err)
+			}
+			if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "control-chars-test", Config: cfg}); err != nil {
+				t.Fatalf("init failed: %v", err)
+			}
+
+			_, err = prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{"CONTROL_CHARS_TEST_VALUE"}})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if err != nil && !strings.Contains(status.Convert(err).Message(), "CONTROL_CHARS_TEST_VALUE") {
+				t.Errorf("expected error to name the variable, got %q", status.Convert(err).Message())
+			}
+		})
+	}
+}
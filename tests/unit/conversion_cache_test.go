@@ -0,0 +1,162 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// largeJSON builds a JSON array of n small objects
+func largeJSON(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":%d,"name":"item-%d","enabled":true}`, i, i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+// newCacheTestProvider initializes a provider with the given conversion cache size
+func newCacheTestProvider(tb testing.TB, cacheSize int) *provider.Provider {
+	tb.Helper()
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	cfg, err := structpb.NewStruct(map[string]interface{}{"conversion_cache_size": cacheSize})
+	if err != nil {
+		tb.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "cache-test", Config: cfg}); err != nil {
+		tb.Fatalf("init failed: %v", err)
+	}
+	return prov
+}
+
+// Test that the conversion cache reduces allocations on repeated JSON fetches
+func TestConversionCacheReducesAllocations(t *testing.T) {
+	t.Setenv("CONVERSION_CACHE_TEST_JSON", largeJSON(200))
+	req := &pb.FetchRequest{Path: []string{"CONVERSION_CACHE_TEST_JSON"}}
+
+	allocs := func(cacheSize int) float64 {
+		prov := newCacheTestProvider(t, cacheSize)
+		var fetchErr error
+		n := testing.AllocsPerRun(20, func() {
+			if _, err := prov.Fetch(context.Background(), req); err != nil {
+				fetchErr = err
+			}
+		})
+		if fetchErr != nil {
+			t.Fatalf("fetch failed: %v", fetchErr)
+		}
+		return n
+	}
+
+	uncached := allocs(0)
+	cached := allocs(8)
+	t.Logf("allocations per fetch: uncached=%.0f cached=%.0f", uncached, cached)
+	if cached >= uncached*0.75 {
+		t.Errorf("expected cached fetches to allocate noticeably less, got cached=%.0f uncached=%.0f", cached, uncached)
+	}
+}
+
+// Test that cached fetches return the same value and re-Init invalidates the cache
+func TestConversionCacheInvalidatedOnInit(t *testing.T) {
+	t.Setenv("CONVERSION_CACHE_TEST_VALUE", "42")
+	req := &pb.FetchRequest{Path: []string{"CONVERSION_CACHE_TEST_VALUE"}}
+	prov := newCacheTestProvider(t, 4)
+
+	for i := 0; i < 2; i++ {
+		resp, err := prov.Fetch(context.Background(), req)
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if got := resp.Value.Fields["value"].GetNumberValue(); got != 42 {
+			t.Errorf("fetch %d: got %v, want 42", i, got)
+		}
+	}
+
+	// Same raw value, new config: the cached number must not be reused
+	cfg, err := structpb.NewStruct(map[string]interface{}{"conversion_cache_size": 4, "enable_type_conversion": false})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "cache-test", Config: cfg}); err != nil {
+		t.Fatalf("re-init failed: %v", err)
+	}
+	resp, err := prov.Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "42" {
+		t.Errorf("after re-init: got %v, want string \"42\"", resp.Value.Fields["value"])
+	}
+}
+
+// Test that changes to the custom converter registry invalidate cached results
+func TestConversionCacheCustomConverterChanges(t *testing.T) {
+	t.Setenv("CONVERSION_CACHE_TEST_CUSTOM", "on")
+	req := &pb.FetchRequest{Path: []string{"CONVERSION_CACHE_TEST_CUSTOM"}}
+	converter.RegisterConverter("test-cache-switch", func(value string) (interface{}, bool) {
+		return value == "on", value == "on"
+	})
+	t.Cleanup(func() { converter.UnregisterConverter("test-cache-switch") })
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	cfg, err := structpb.NewStruct(map[string]interface{}{
+		"conversion_cache_size": 4,
+		"custom_converters":     []interface{}{"test-cache-switch"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "cache-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	resp, err := prov.Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetBoolValue(); !got {
+		t.Errorf("with the converter registered: got %v, want true", resp.Value.Fields["value"])
+	}
+
+	// Without the converter the raw value is a plain string, not the cached boolean
+	converter.UnregisterConverter("test-cache-switch")
+	resp, err = prov.Fetch(context.Background(), req)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "on" {
+		t.Errorf("after unregistering: got %v, want string \"on\"", resp.Value.Fields["value"])
+	}
+}
+
+// Benchmark repeated JSON fetches with and without the conversion cache
+//
+// Usage:
+//
+//	go test -bench=BenchmarkConversionCache -benchmem ./tests/unit/
+func BenchmarkConversionCache(b *testing.B) {
+	b.Setenv("CONVERSION_CACHE_BENCH_JSON", largeJSON(200))
+	req := &pb.FetchRequest{Path: []string{"CONVERSION_CACHE_BENCH_JSON"}}
+
+	for _, size := range []int{0, 8} {
+		b.Run(fmt.Sprintf("cache_size_%d", size), func(b *testing.B) {
+			prov := newCacheTestProvider(b, size)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := prov.Fetch(context.Background(), req); err != nil {
+					b.Fatalf("fetch failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package unit
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -185,6 +188,114 @@ func TestJSONParsing(t *testing.T) {
 	}
 }
 
+// Test that json_numbers_as_strings preserves the exact number literals
+func TestJSONNumbersAsStrings(t *testing.T) {
+	input := `{"id":"00123","account":12345678901234567890,"rate":1.50,"limits":[7e2,-0]}`
+
+	got, typ, err := converter.ConvertValueWithOptions(input, converter.Options{
+		EnableTypeConversion: true,
+		EnableJSONParsing:    true,
+		JSONNumbersAsStrings: true,
+	})
+	if err != nil {
+		t.Fatalf("ConvertValueWithOptions() error = %v", err)
+	}
+	if typ != "object" {
+		t.Errorf("type got %q, want %q", typ, "object")
+	}
+	want := map[string]interface{}{
+		"id":      "00123",
+		"account": "12345678901234567890",
+		"rate":    "1.50",
+		"limits":  []interface{}{"7e2", "-0"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	// Without the option numbers are float64 and lose precision
+	got, _, err = converter.ConvertValue(input, true, true)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	if _, ok := got.(map[string]interface{})["account"].(float64); !ok {
+		t.Errorf("expected float64 account without the option, got %T", got.(map[string]interface{})["account"])
+	}
+
+	// Trailing data is still rejected
+	if _, err := converter.TryJSONWithOptions(`{"a":1} {"b":2}`, true); !errors.Is(err, converter.ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON for trailing data, got %v", err)
+	}
+}
+
+// Test newline_as_array splitting of multi-line values
+func TestNewlineAsArray(t *testing.T) {
+	opts := converter.Options{
+		EnableTypeConversion: true,
+		EnableJSONParsing:    true,
+		NewlineAsArray:       true,
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		want     interface{}
+		wantType string
+	}{
+		{"multi-line value", "api.example.com\n  web.example.com \r\n\nlocalhost\n", []interface{}{"api.example.com", "web.example.com", "localhost"}, "array"},
+		{"single-line value", "api.example.com", "api.example.com", "string"},
+		{"single-line number", "42", float64(42), "integer"},
+		{"multi-line JSON stays JSON", "[\n  \"a\",\n  \"b\"\n]", []interface{}{"a", "b"}, "array"},
+		{"multi-line JSON object", "{\n\"a\": 1\n}", map[string]interface{}{"a": float64(1)}, "object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, opts)
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || typ != tt.wantType {
+				t.Errorf("got %#v (%s), want %#v (%s)", got, typ, tt.want, tt.wantType)
+			}
+		})
+	}
+}
+
+// Test that numeric type strings distinguish integers from floats
+func TestNumericTypeLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+	}{
+		{"positive integer", "42", "integer"},
+		{"negative integer", "-42", "integer"},
+		{"zero", "0", "integer"},
+		{"float", "3.14", "float"},
+		{"float with zero fraction", "42.0", "float"},
+		{"leading dot float", ".5", "float"},
+		{"scientific notation", "1e10", "float"},
+		{"uppercase exponent", "1.23E-10", "float"},
+		{"infinity", "Inf", "float"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValue(tt.input, true, true)
+			if err != nil {
+				t.Fatalf("ConvertValue() error = %v", err)
+			}
+			if _, ok := got.(float64); !ok {
+				t.Errorf("expected float64 value, got %T", got)
+			}
+			if typ != tt.wantType {
+				t.Errorf("type: got %q, want %q", typ, tt.wantType)
+			}
+		})
+	}
+}
+
 // T060: Unit test for conversion precedence (number before boolean)
 func TestConversionPrecedence(t *testing.T) {
 	tests := []struct {
@@ -265,6 +376,305 @@ func TestJSONParsingErrors(t *testing.T) {
 	}
 }
 
+// Test relaxed JSON parsing of comments and trailing commas
+func TestRelaxedJSON(t *testing.T) {
+	opts := converter.Options{
+		EnableTypeConversion: true,
+		EnableJSONParsing:    true,
+		EnableRelaxedJSON:    true,
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{"trailing comma in array", `[1,2,3,]`, []interface{}{float64(1), float64(2), float64(3)}},
+		{"trailing comma in object", `{"key":"value",}`, map[string]interface{}{"key": "value"}},
+		{"line comment", "{\n  // database host\n  \"host\": \"localhost\"\n}", map[string]interface{}{"host": "localhost"}},
+		{"block comment and trailing comma", `{"port": 5432, /* default */ }`, map[string]interface{}{"port": float64(5432)}},
+		{"comment markers inside strings are kept", `{"url": "http://example.com/*x*/",}`, map[string]interface{}{"url": "http://example.com/*x*/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := converter.ConvertValueWithOptions(tt.input, opts)
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// Malformed JSON is still rejected in relaxed mode
+	if _, _, err := converter.ConvertValueWithOptions(`{"key":"value"`, opts); !errors.Is(err, converter.ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+}
+
+// Test strict_conversion rejecting integers beyond the exact float64 range
+func TestStrictConversion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		strict  bool
+		wantErr bool
+	}{
+		{"beyond 2^53 strict", "9007199254740993", true, true},
+		{"negative beyond 2^53 strict", "-9007199254740993", true, true},
+		{"beyond 2^53 lenient", "9007199254740993", false, false},
+		{"2^53 is exact", "9007199254740992", true, false},
+		{"small integer", "42", true, false},
+		{"float literal not checked", "0.1", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				StrictConversion:     tt.strict,
+			})
+			if tt.wantErr {
+				if !errors.Is(err, converter.ErrLossyConversion) {
+					t.Errorf("expected ErrLossyConversion, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// Test quoted_as_string forcing double-quoted values to strings
+func TestQuotedAsString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantVal  interface{}
+		wantType string
+	}{
+		{"quoted number", `"42"`, "42", "string"},
+		{"quoted boolean", `"true"`, "true", "string"},
+		{"unquoted number", "42", float64(42), "integer"},
+		{"single quotes untouched", `'42'`, "'42'", "string"},
+		{"empty quoted", `""`, "", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				QuotedAsString:       true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantVal || typ != tt.wantType {
+				t.Errorf("ConvertValueWithOptions(%q) = (%v, %q), want (%v, %q)", tt.input, got, typ, tt.wantVal, tt.wantType)
+			}
+		})
+	}
+}
+
+// Test ErrorClass reducing errors to their sentinel without value details
+func TestErrorClass(t *testing.T) {
+	_, _, err := converter.ConvertValue(`{"token": sk_live_secret}`, false, true)
+	if err == nil {
+		t.Fatal("expected error for malformed JSON")
+	}
+	class := converter.ErrorClass(err)
+	if class != converter.ErrInvalidJSON {
+		t.Errorf("ErrorClass() = %v, want ErrInvalidJSON", class)
+	}
+	if strings.Contains(class.Error(), "sk_live_secret") || strings.Contains(class.Error(), "'s'") {
+		t.Errorf("ErrorClass() leaks value details: %q", class.Error())
+	}
+	if got := converter.ErrorClass(errors.New("boom")); got != converter.ErrConversion {
+		t.Errorf("ErrorClass(unknown) = %v, want ErrConversion", got)
+	}
+}
+
+// Test duration parsing of Go and ISO 8601 durations
+func TestDurationConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantVal  interface{}
+		wantType string
+	}{
+		{"go seconds", "30s", float64(30), "integer"},
+		{"iso seconds", "PT30S", float64(30), "integer"},
+		{"iso hours and minutes", "PT1H30M", float64(5400), "integer"},
+		{"go hours and minutes", "1h30m", float64(5400), "integer"},
+		{"iso zero", "PT0S", float64(0), "integer"},
+		{"iso days and hours", "P1DT12H", float64(129600), "integer"},
+		{"iso fractional seconds", "PT1.5S", float64(1.5), "float"},
+		{"iso missing designator", "P30", "P30", "string"},
+		{"iso bare time marker", "PT", "PT", "string"},
+		{"iso months unsupported", "P1M", "P1M", "string"},
+		{"iso repeated time marker", "PT1HT5M", "PT1HT5M", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion:  true,
+				EnableDurationParsing: true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantVal || typ != tt.wantType {
+				t.Errorf("ConvertValueWithOptions(%q) = (%v, %q), want (%v, %q)", tt.input, got, typ, tt.wantVal, tt.wantType)
+			}
+		})
+	}
+
+	// Durations stay strings unless enabled
+	if got, _, _ := converter.ConvertValue("PT30S", true, false); got != "PT30S" {
+		t.Errorf("expected PT30S to stay a string when duration parsing is disabled, got %v", got)
+	}
+}
+
+// Test conversion warnings for ambiguous values
+func TestConversionWarnings(t *testing.T) {
+	opts := converter.Options{EnableTypeConversion: true}
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"1", []string{"value interpreted as integer; could be boolean"}},
+		{"yes", []string{"value interpreted as boolean; could be string"}},
+		{"007", []string{"value interpreted as integer; leading zeros are dropped, could be string"}},
+		{"42", nil},
+		{"true", nil},
+		{"hello", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := converter.Warnings(tt.input, opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Warnings(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if got := converter.Warnings("1", converter.Options{}); got != nil {
+		t.Errorf("expected no warnings without type conversion, got %v", got)
+	}
+
+	// Quote handling matches conversion
+	stripped := converter.Options{EnableTypeConversion: true, StripQuotes: true}
+	if got := converter.Warnings(`"1"`, stripped); len(got) != 1 {
+		t.Errorf("expected a warning for a stripped quoted 1, got %v", got)
+	}
+	quoted := converter.Options{EnableTypeConversion: true, StripQuotes: true, QuotedAsString: true}
+	if got := converter.Warnings(`"1"`, quoted); got != nil {
+		t.Errorf("expected no warnings for a quoted string, got %v", got)
+	}
+}
+
+// Test max_array_length limiting parsed array sizes
+func TestMaxArrayLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"top-level under cap", "[1,2,3]", false},
+		{"top-level at cap", "[1,2,3,4]", false},
+		{"top-level over cap", "[1,2,3,4,5]", true},
+		{"nested over cap", `{"items":[1,2,3,4,5]}`, true},
+		{"nested under cap", `{"items":[[1,2],[3,4]]}`, false},
+		{"lines over cap", "a\nb\nc\nd\ne", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableJSONParsing: true,
+				NewlineAsArray:    true,
+				MaxArrayLength:    4,
+			})
+			if tt.wantErr != errors.Is(err, converter.ErrArrayTooLong) {
+				t.Errorf("ConvertValueWithOptions(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// Zero disables the cap
+	if _, _, err := converter.ConvertValueWithOptions("[1,2,3,4,5]", converter.Options{EnableJSONParsing: true}); err != nil {
+		t.Errorf("expected no limit by default, got %v", err)
+	}
+}
+
+// Test NumericBooleans converting 0/1 to booleans
+func TestNumericBooleans(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantVal  interface{}
+		wantType string
+	}{
+		{"1", true, "boolean"},
+		{"0", false, "boolean"},
+		{"2", float64(2), "integer"},
+		{"01", float64(1), "integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				NumericBooleans:      true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantVal || typ != tt.wantType {
+				t.Errorf("ConvertValueWithOptions(%q) = (%v, %q), want (%v, %q)", tt.input, got, typ, tt.wantVal, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestRecognizeTF(t *testing.T) {
+	tests := []struct {
+		input    string
+		enabled  bool
+		wantVal  interface{}
+		wantType string
+	}{
+		{"t", true, true, "boolean"},
+		{"F", true, false, "boolean"},
+		{" T ", true, true, "boolean"},
+		{"tf", true, "tf", "string"},
+		{"1", true, float64(1), "integer"},
+		{"t", false, "t", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q enabled=%v", tt.input, tt.enabled), func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				RecognizeTF:          tt.enabled,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantVal || typ != tt.wantType {
+				t.Errorf("ConvertValueWithOptions(%q) = (%v, %q), want (%v, %q)", tt.input, got, typ, tt.wantVal, tt.wantType)
+			}
+		})
+	}
+}
+
 // T063: Unit test for empty string handling
 func TestEmptyStringHandling(t *testing.T) {
 	tests := []struct {
@@ -298,6 +708,240 @@ func TestEmptyStringHandling(t *testing.T) {
 	}
 }
 
+// Test that trim_whitespace normalizes whitespace-only values to empty strings
+func TestTrimWhitespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{"whitespace only", "   ", ""},
+		{"mixed whitespace", " \t\n ", ""},
+		{"padded string", "  localhost  ", "localhost"},
+		{"padded number", " 42\n", float64(42)},
+		{"padded boolean", "\ttrue ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				EnableJSONParsing:    true,
+				TrimWhitespace:       true,
+			})
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test null token conversion with null_as_null enabled and disabled
+func TestNullConversion(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		nullAsNull bool
+		wantNil    bool
+		wantType   string
+	}{
+		{"lowercase null with flag", "null", true, true, "null"},
+		{"uppercase NULL with flag", "NULL", true, true, "null"},
+		{"nil with flag", "nil", true, true, "null"},
+		{"nullish with flag stays string", "nullish", true, false, "string"},
+		{"null without flag stays string", "null", false, false, "string"},
+		{"NULL without flag stays string", "NULL", false, false, "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				EnableJSONParsing:    true,
+				NullAsNull:           tt.nullAsNull,
+			})
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+
+			if tt.wantNil && got != nil {
+				t.Errorf("expected nil, got %v (%T)", got, got)
+			}
+			if !tt.wantNil {
+				if gotStr, ok := got.(string); !ok || gotStr != tt.input {
+					t.Errorf("expected string %q, got %v (%T)", tt.input, got, got)
+				}
+			}
+			if typ != tt.wantType {
+				t.Errorf("type: got %q, want %q", typ, tt.wantType)
+			}
+		})
+	}
+}
+
+// Test size conversion with enable_size_parsing
+func TestSizeConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     interface{}
+		wantType string
+	}{
+		{"SI megabytes", "10MB", float64(10_000_000), "integer"},
+		{"binary gibibytes", "2GiB", float64(2 * 1024 * 1024 * 1024), "integer"},
+		{"SI kilobytes lowercase", "512kb", float64(512_000), "integer"},
+		{"plain number stays number", "512", float64(512), "integer"},
+		{"unknown suffix stays string", "10XB", "10XB", "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, typ, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				EnableSizeParsing:    true,
+			})
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+			if typ != tt.wantType {
+				t.Errorf("type: got %q, want %q", typ, tt.wantType)
+			}
+		})
+	}
+}
+
+// Test that size values stay strings when size parsing is disabled
+func TestSizeConversionDisabled(t *testing.T) {
+	got, _, err := converter.ConvertValue("10MB", true, true)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	if got != "10MB" {
+		t.Errorf("expected string %q, got %v (%T)", "10MB", got, got)
+	}
+}
+
+// Test quote stripping with strip_quotes enabled
+func TestStripQuotes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{"double-quoted string", `"localhost"`, "localhost"},
+		{"single-quoted string", `'localhost'`, "localhost"},
+		{"unquoted string", "localhost", "localhost"},
+		{"mismatched quotes kept", `"localhost'`, `"localhost'`},
+		{"lone quote kept", `"`, `"`},
+		{"quoted number converts after stripping", `"42"`, float64(42)},
+		{"empty quoted string", `""`, ""},
+		{"JSON string values keep their quotes", `{"host":"localhost"}`, map[string]interface{}{"host": "localhost"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := converter.ConvertValueWithOptions(tt.input, converter.Options{
+				EnableTypeConversion: true,
+				EnableJSONParsing:    true,
+				StripQuotes:          true,
+			})
+			if err != nil {
+				t.Fatalf("ConvertValueWithOptions() error = %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+// parseSemver is a test converter splitting "MAJOR.MINOR.PATCH" into an object
+func parseSemver(value string) (interface{}, bool) {
+	parts := strings.Split(strings.TrimPrefix(value, "v"), ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	names := []string{"major", "minor", "patch"}
+	result := make(map[string]interface{}, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		result[names[i]] = float64(n)
+	}
+	return result, true
+}
+
+// Test custom converters registered via RegisterConverter
+func TestCustomConverters(t *testing.T) {
+	converter.RegisterConverter("test-semver", parseSemver)
+	t.Cleanup(func() { converter.UnregisterConverter("test-semver") })
+
+	opts := converter.Options{
+		EnableTypeConversion: true,
+		EnableJSONParsing:    true,
+		CustomConverters:     []string{"test-semver"},
+	}
+
+	got, typ, err := converter.ConvertValueWithOptions("v1.22.3", opts)
+	if err != nil {
+		t.Fatalf("ConvertValueWithOptions() error = %v", err)
+	}
+	want := map[string]interface{}{"major": float64(1), "minor": float64(22), "patch": float64(3)}
+	if fmt.Sprint(got) != fmt.Sprint(want) || typ != "object" {
+		t.Errorf("got %v (%s), want %v (object)", got, typ, want)
+	}
+
+	// Values the converter rejects fall through to the built-in conversions
+	got, typ, err = converter.ConvertValueWithOptions("1.5", opts)
+	if err != nil {
+		t.Fatalf("ConvertValueWithOptions() error = %v", err)
+	}
+	if got != 1.5 || typ != "float" {
+		t.Errorf("got %v (%s), want 1.5 (float)", got, typ)
+	}
+
+	// Registered converters are not applied unless configured
+	opts.CustomConverters = nil
+	got, _, err = converter.ConvertValueWithOptions("v1.22.3", opts)
+	if err != nil {
+		t.Fatalf("ConvertValueWithOptions() error = %v", err)
+	}
+	if got != "v1.22.3" {
+		t.Errorf("expected unconverted string, got %v (%T)", got, got)
+	}
+
+	// Custom converters are part of type conversion and disabled with it
+	opts.CustomConverters = []string{"test-semver"}
+	opts.EnableTypeConversion = false
+	got, _, err = converter.ConvertValueWithOptions("v1.22.3", opts)
+	if err != nil {
+		t.Fatalf("ConvertValueWithOptions() error = %v", err)
+	}
+	if got != "v1.22.3" {
+		t.Errorf("expected unconverted string with type conversion disabled, got %v (%T)", got, got)
+	}
+
+	// The registry generation changes with every registration
+	generation := converter.RegistryGeneration()
+
+	// Unregistered converters are no longer available
+	converter.UnregisterConverter("test-semver")
+	if converter.RegistryGeneration() == generation {
+		t.Error("expected the registry generation to change on unregister")
+	}
+	if converter.HasConverter("test-semver") {
+		t.Error("expected test-semver to be unregistered")
+	}
+}
+
 // Test edge cases
 func TestEdgeCases(t *testing.T) {
 	tests := []struct {
@@ -378,3 +1022,95 @@ func createDeeplyNestedJSON(depth int) string {
 	}
 	return builder.String()
 }
+
+// Test that CompileSchema rejects unsupported keywords and invalid patterns
+func TestCompileSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  map[string]interface{}
+		wantErr bool
+	}{
+		{"supported keywords with annotations", map[string]interface{}{
+			"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "server",
+			"type": "object", "properties": map[string]interface{}{"host": map[string]interface{}{"type": "string", "pattern": "^[a-z.]+$"}},
+		}, false},
+		{"oneOf", map[string]interface{}{"oneOf": []interface{}{map[string]interface{}{"type": "string"}}}, true},
+		{"ref", map[string]interface{}{"$ref": "#/definitions/server"}, true},
+		{"format", map[string]interface{}{"type": "string", "format": "email"}, true},
+		{"nested unsupported keyword", map[string]interface{}{
+			"items": map[string]interface{}{"anyOf": []interface{}{}},
+		}, true},
+		{"invalid pattern", map[string]interface{}{"pattern": "[unclosed"}, true},
+		{"tuple items", map[string]interface{}{"items": []interface{}{map[string]interface{}{}}}, true},
+		{"type list", map[string]interface{}{"type": []interface{}{"string", "null"}}, false},
+		{"unknown type", map[string]interface{}{"type": "text"}, true},
+		{"unknown type in list", map[string]interface{}{"type": []interface{}{"string", "int"}}, true},
+		{"non-string type", map[string]interface{}{"type": []interface{}{"string", 42.0}}, true},
+		{"empty type list", map[string]interface{}{"type": []interface{}{}}, true},
+		{"nested unknown type", map[string]interface{}{
+			"properties": map[string]interface{}{"port": map[string]interface{}{"type": "int"}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converter.CompileSchema(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompileSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, converter.ErrInvalidSchema) {
+				t.Errorf("expected ErrInvalidSchema, got %v", err)
+			}
+		})
+	}
+
+	// Compiled patterns are applied during validation
+	schema, err := converter.CompileSchema(map[string]interface{}{
+		"items": map[string]interface{}{"type": "string", "pattern": "^v[0-9]+$"},
+	})
+	if err != nil {
+		t.Fatalf("CompileSchema() error = %v", err)
+	}
+	if err := schema.Validate([]interface{}{"v1", "v22"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := schema.Validate([]interface{}{"v1", "latest"}); !errors.Is(err, converter.ErrSchemaValidation) {
+		t.Errorf("Validate() error = %v, want ErrSchemaValidation", err)
+	}
+}
+
+// Test schema validation of values parsed with json_numbers_as_strings
+func TestSchemaNumbersAsStrings(t *testing.T) {
+	schema, err := converter.CompileSchema(map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "integer", "minimum": 1.0},
+	})
+	if err != nil {
+		t.Fatalf("CompileSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   []interface{}
+		wantErr bool
+	}{
+		{"integer literals", []interface{}{"1", "12345678901234567890"}, false},
+		{"below minimum", []interface{}{"0"}, true},
+		{"fraction", []interface{}{"1.5"}, true},
+		{"non-numeric string", []interface{}{"one"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateNumbersAsStrings(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNumbersAsStrings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without the option, number literal strings are only strings
+	if err := schema.Validate([]interface{}{"1"}); !errors.Is(err, converter.ErrSchemaValidation) {
+		t.Errorf("Validate() error = %v, want ErrSchemaValidation", err)
+	}
+}
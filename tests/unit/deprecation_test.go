@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that fetching a deprecated variable warns once per name and still returns the value
+func TestDeprecatedVariableWarning(t *testing.T) {
+	t.Setenv("DEPRECATED_TEST_DB_URL", "postgres://localhost")
+	t.Setenv("DEPRECATED_TEST_OTHER", "other")
+
+	var logs bytes.Buffer
+	prov := provider.New(logger.NewWithOutput(logger.WARN, &logs))
+	cfg, err := structpb.NewStruct(map[string]interface{}{
+		"deprecated_variables": map[string]interface{}{
+			"DEPRECATED_TEST_DB_URL": "use DATABASE_URL instead",
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "deprecation-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		for _, name := range []string{"DEPRECATED_TEST_DB_URL", "DEPRECATED_TEST_OTHER"} {
+			resp, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{name}})
+			if err != nil {
+				t.Fatalf("fetch %s failed: %v", name, err)
+			}
+			if resp.Value.Fields["value"].GetStringValue() == "" {
+				t.Errorf("fetch %s returned an empty value", name)
+			}
+		}
+	}
+
+	output := logs.String()
+	want := "environment variable DEPRECATED_TEST_DB_URL is deprecated: use DATABASE_URL instead"
+	if n := strings.Count(output, want); n != 1 {
+		t.Errorf("expected deprecation warning exactly once, got %d in:\n%s", n, output)
+	}
+	if strings.Contains(output, "DEPRECATED_TEST_OTHER is deprecated") {
+		t.Errorf("unexpected warning for non-deprecated variable:\n%s", output)
+	}
+}
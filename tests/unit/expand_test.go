@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
+)
+
+// mapLookup returns a resolver.LookupFunc backed by a map
+func mapLookup(vars map[string]string) resolver.LookupFunc {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestExpandReferences(t *testing.T) {
+	vars := map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PORT": "5432",
+		"DB_ADDR": "${DB_HOST}:${DB_PORT}",
+		"CYCLE_A": "${CYCLE_B}",
+		"CYCLE_B": "${CYCLE_A}",
+		"EMPTY":   "",
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		strict    bool
+		want      string
+		wantError error
+	}{
+		{"no references", "plain value", false, "plain value", nil},
+		{"simple expansion", "postgres://${DB_HOST}:${DB_PORT}/app", false, "postgres://localhost:5432/app", nil},
+		{"nested references", "postgres://${DB_ADDR}/app", false, "postgres://localhost:5432/app", nil},
+		{"empty referenced value", "a${EMPTY}b", false, "ab", nil},
+		{"unresolved reference kept", "host=${MISSING_VAR}", false, "host=${MISSING_VAR}", nil},
+		{"unterminated reference kept", "host=${DB_HOST", false, "host=${DB_HOST", nil},
+		{"unresolved reference strict", "host=${MISSING_VAR}", true, "", resolver.ErrUnresolvedReference},
+		{"reference cycle", "${CYCLE_A}", false, "", resolver.ErrExpansionTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.ExpandReferences(tt.input, mapLookup(vars), tt.strict, resolver.DefaultMaxExpansionDepth)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
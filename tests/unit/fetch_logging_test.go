@@ -0,0 +1,43 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that log_fetch_success logs successful fetches at info level without the value
+func TestLogFetchSuccess(t *testing.T) {
+	t.Setenv("LOG_FETCH_TEST_SECRET", "s3cr3t-value")
+
+	for _, enabled := range []bool{true, false} {
+		var logs bytes.Buffer
+		prov := provider.New(logger.NewWithOutput(logger.INFO, &logs))
+		cfg, err := structpb.NewStruct(map[string]interface{}{"log_fetch_success": enabled})
+		if err != nil {
+			t.Fatalf("failed to create config: %v", err)
+		}
+		if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "log-fetch-test", Config: cfg}); err != nil {
+			t.Fatalf("init failed: %v", err)
+		}
+		if _, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{"LOG_FETCH_TEST_SECRET"}}); err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+
+		output := logs.String()
+		logged := strings.Contains(output, "successfully fetched LOG_FETCH_TEST_SECRET")
+		if logged != enabled {
+			t.Errorf("log_fetch_success=%v: success logged = %v, output:\n%s", enabled, logged, output)
+		}
+		if strings.Contains(output, "s3cr3t-value") {
+			t.Errorf("log_fetch_success=%v: value leaked into logs:\n%s", enabled, output)
+		}
+	}
+}
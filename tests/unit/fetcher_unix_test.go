@@ -101,3 +101,31 @@ func TestUnixCaseSensitivity(t *testing.T) {
 	t.Logf("  %s = %q", testKey, valueUpper)
 	t.Logf("  %s = %q", testKeyLower, valueLower)
 }
+
+// Test case-insensitive lookup finding a differently-cased variable on Unix
+func TestCaseInsensitiveLookup(t *testing.T) {
+	actual := fmt.Sprintf("Test_Insensitive_Host_%d", os.Getpid())
+	requested := fmt.Sprintf("TEST_INSENSITIVE_HOST_%d", os.Getpid())
+	t.Setenv(actual, "db.local")
+
+	f := fetcher.New()
+	if _, err := f.Fetch(requested); err != fetcher.ErrNotFound {
+		t.Fatalf("expected case-sensitive miss, got %v", err)
+	}
+
+	f.SetCaseInsensitive(true)
+	f.Clear()
+	value, err := f.Fetch(requested)
+	if err != nil {
+		t.Fatalf("expected case-insensitive match, got %v", err)
+	}
+	if value != "db.local" {
+		t.Errorf("expected %q, got %q", "db.local", value)
+	}
+
+	// An exact match still takes precedence
+	t.Setenv(requested, "exact")
+	if value, _ := f.FetchLive(requested); value != "exact" {
+		t.Errorf("expected exact match %q, got %q", "exact", value)
+	}
+}
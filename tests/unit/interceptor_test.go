@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+)
+
+func TestLoggingInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		handlerErr error
+		wantStatus string
+	}{
+		{"successful call", nil, "OK"},
+		{"failed call", status.Error(codes.NotFound, "missing"), "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			interceptor := provider.LoggingInterceptor(logger.NewWithOutput(logger.INFO, &buf))
+
+			info := &grpc.UnaryServerInfo{FullMethod: "/nomos.provider.v1.ProviderService/Fetch"}
+			handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+				return "response", tt.handlerErr
+			}
+
+			resp, err := interceptor(context.Background(), "request", info, handler)
+			if resp != "response" {
+				t.Errorf("expected handler response to pass through, got %v", resp)
+			}
+			if !errors.Is(err, tt.handlerErr) {
+				t.Errorf("expected handler error to pass through, got %v", err)
+			}
+
+			line := buf.String()
+			if !strings.Contains(line, info.FullMethod) {
+				t.Errorf("expected log line to contain method, got %q", line)
+			}
+			if !strings.Contains(line, "status "+tt.wantStatus) {
+				t.Errorf("expected log line to contain status %s, got %q", tt.wantStatus, line)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that the metrics handler reports fetch, error, and cache counters
+func TestMetricsHandler(t *testing.T) {
+	t.Setenv("METRICS_TEST_VAR", "value")
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	cfg, err := structpb.NewStruct(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "metrics-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	// One miss, one hit, one NotFound, one InvalidArgument
+	for _, path := range [][]string{{"METRICS_TEST_VAR"}, {"METRICS_TEST_VAR"}, {"METRICS_TEST_MISSING_VAR"}, {"raw:"}} {
+		_, _ = prov.Fetch(context.Background(), &pb.FetchRequest{Path: path})
+	}
+
+	rec := httptest.NewRecorder()
+	prov.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain content type, got %q", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE nomos_env_fetch_total counter",
+		"nomos_env_fetch_total 4\n",
+		`nomos_env_fetch_errors_total{code="NotFound"} 1` + "\n",
+		`nomos_env_fetch_errors_total{code="InvalidArgument"} 1` + "\n",
+		"nomos_env_cache_hits_total 1\n",
+		"nomos_env_cache_misses_total 2\n",
+		"# TYPE nomos_env_cache_hit_ratio gauge",
+		"nomos_env_cache_entries 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
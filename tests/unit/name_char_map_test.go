@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that name_char_map maps dotted paths to underscore variable names in Fetch
+func TestFetchNameCharMap(t *testing.T) {
+	t.Setenv("CONFIG_API_ENDPOINT", "https://api.local")
+	t.Setenv("config_api_timeout", "30")
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	cfg, err := structpb.NewStruct(map[string]interface{}{
+		"name_char_map":          map[string]interface{}{".": "_"},
+		"enable_type_conversion": false,
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "name-char-map-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"config.api", "endpoint"}, "https://api.local"},
+		{[]string{"config.api.timeout"}, "30"},
+	}
+	for _, tt := range tests {
+		resp, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: tt.path})
+		if err != nil {
+			t.Fatalf("fetch %v failed: %v", tt.path, err)
+		}
+		if got := resp.Value.Fields["value"].GetStringValue(); got != tt.want {
+			t.Errorf("fetch %v = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -413,3 +413,77 @@ func TestPrefixInvalidMode(t *testing.T) {
 		})
 	}
 }
+
+// Test separator handling at the seam between prefix and name
+func TestJoinPrefix(t *testing.T) {
+	tests := []struct {
+		name            string
+		prefix          string
+		varName         string
+		prefixSeparator string
+		want            string
+	}{
+		{"prefix ending with separator", "MYAPP_", "DATABASE_HOST", "", "MYAPP_DATABASE_HOST"},
+		{"prefix not ending with separator", "MYAPP", "DATABASE_HOST", "", "MYAPPDATABASE_HOST"},
+		{"doubled separator collapsed", "MYAPP_", "_DATABASE_HOST", "", "MYAPP_DATABASE_HOST"},
+		{"name beginning with separator", "MYAPP", "_DATABASE_HOST", "", "MYAPP_DATABASE_HOST"},
+		{"empty prefix", "", "DATABASE_HOST", "", "DATABASE_HOST"},
+		{"explicit separator inserted", "MYAPP", "DATABASE_HOST", "_", "MYAPP_DATABASE_HOST"},
+		{"explicit separator not doubled", "MYAPP_", "DATABASE_HOST", "_", "MYAPP_DATABASE_HOST"},
+		{"explicit separator with leading name separator", "MYAPP_", "_DATABASE_HOST", "_", "MYAPP_DATABASE_HOST"},
+		{"explicit separator differs from path separator", "MYAPP", "DATABASE_HOST", "__", "MYAPP__DATABASE_HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolver.JoinPrefix(tt.prefix, tt.varName, "_", tt.prefixSeparator)
+			if got != tt.want {
+				t.Errorf("JoinPrefix() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test prefix_separator and auto_prefix_separator applied through the
+// resolver in prepend mode: an explicit prefix_separator wins, otherwise
+// auto_prefix_separator places Separator at the seam, and with neither the
+// prefix is prepended as-is
+func TestResolverPrefixSeparator(t *testing.T) {
+	tests := []struct {
+		name            string
+		prefix          string
+		prefixSeparator string
+		auto            bool
+		path            []string
+		want            string
+	}{
+		{"default keeps separator-less prefix", "MYAPP", "", false, []string{"database", "host"}, "MYAPPDATABASE_HOST"},
+		{"default keeps prefix separator", "MYAPP_", "", false, []string{"database", "host"}, "MYAPP_DATABASE_HOST"},
+		{"default collapses doubled separator", "MYAPP_", "", false, []string{"_database", "host"}, "MYAPP_DATABASE_HOST"},
+		{"explicit separator inserted", "MYAPP", "_", false, []string{"database", "host"}, "MYAPP_DATABASE_HOST"},
+		{"auto appends missing separator", "MYAPP", "", true, []string{"database", "host"}, "MYAPP_DATABASE_HOST"},
+		{"auto keeps existing separator", "MYAPP_", "", true, []string{"database", "host"}, "MYAPP_DATABASE_HOST"},
+		{"auto with empty prefix", "", "", true, []string{"database", "host"}, "DATABASE_HOST"},
+		{"explicit separator wins over auto", "MYAPP", "__", true, []string{"database", "host"}, "MYAPP__DATABASE_HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:           "_",
+				CaseTransform:       "upper",
+				Prefix:              tt.prefix,
+				PrefixMode:          "prepend",
+				PrefixSeparator:     tt.prefixSeparator,
+				AutoPrefixSeparator: tt.auto,
+			})
+			got, err := r.Transform(tt.path)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Transform() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
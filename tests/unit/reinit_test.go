@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that re-initialising while fetches and health checks are in flight is
+// race-free (run with -race)
+func TestReinitDuringFetches(t *testing.T) {
+	t.Setenv("REINIT_RACE_TEST_VAR", "process")
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	configs := make([]*structpb.Struct, 2)
+	for i, injected := range []map[string]interface{}{
+		{"REINIT_RACE_TEST_INJECTED": "first"},
+		{"REINIT_RACE_TEST_INJECTED": "second"},
+	} {
+		cfg, err := structpb.NewStruct(map[string]interface{}{
+			"injected_variables":          injected,
+			"injected_variables_override": i == 1,
+			"health_include_cache_stats":  true,
+		})
+		if err != nil {
+			t.Fatalf("failed to create config: %v", err)
+		}
+		configs[i] = cfg
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "reinit-test", Config: configs[0]}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	stop := make(chan struct{})
+	var wg, started sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for _, name := range []string{"REINIT_RACE_TEST_VAR", "REINIT_RACE_TEST_INJECTED"} {
+					_, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{name}})
+					// Fetches racing an Init may see it uninitialized
+					if code := status.Code(err); code != codes.OK && code != codes.FailedPrecondition {
+						t.Errorf("fetch of %s failed: %v", name, err)
+					}
+				}
+				if _, err := prov.Health(context.Background(), &pb.HealthRequest{}); err != nil {
+					t.Errorf("health failed: %v", err)
+				}
+			}
+		}()
+	}
+	started.Wait()
+	for i := 0; i < 50; i++ {
+		runtime.Gosched()
+		if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "reinit-test", Config: configs[i%2]}); err != nil {
+			t.Errorf("re-init failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/converter"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that request_timeout_ms bounds a fetch with a slow converter
+func TestRequestTimeout(t *testing.T) {
+	converter.RegisterConverter("test-slow", func(value string) (interface{}, bool) {
+		time.Sleep(50 * time.Millisecond)
+		return nil, false
+	})
+	t.Cleanup(func() { converter.UnregisterConverter("test-slow") })
+	t.Setenv("REQUEST_TIMEOUT_TEST_VAR", "value")
+	req := &pb.FetchRequest{Path: []string{"REQUEST_TIMEOUT_TEST_VAR"}}
+
+	tests := []struct {
+		name      string
+		timeoutMS int
+		wantCode  codes.Code
+	}{
+		{"timeout shorter than conversion", 10, codes.DeadlineExceeded},
+		{"timeout longer than conversion", 5000, codes.OK},
+		{"timeout disabled", 0, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := structpb.NewStruct(map[string]interface{}{
+				"custom_converters":  []interface{}{"test-slow"},
+				"request_timeout_ms": tt.timeoutMS,
+			})
+			if err != nil {
+				t.Fatalf("failed to create config: %v", err)
+			}
+			prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+			if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "timeout-test", Config: cfg}); err != nil {
+				t.Fatalf("init failed: %v", err)
+			}
+
+			_, err = prov.Fetch(context.Background(), req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("expected %v, got %v (%v)", tt.wantCode, got, err)
+			}
+		})
+	}
+}
+
+// Test that a fetch stops before processing the value once its context is done
+func TestFetchStopsAfterDeadline(t *testing.T) {
+	t.Setenv("REQUEST_DEADLINE_TEST_VAR", "${REQUEST_DEADLINE_TEST_VAR}")
+	cfg, err := structpb.NewStruct(map[string]interface{}{"expand_references": true})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, io.Discard))
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "timeout-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	// The self-reference would fail expansion with InvalidArgument if reached
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = prov.Fetch(ctx, &pb.FetchRequest{Path: []string{"REQUEST_DEADLINE_TEST_VAR"}})
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v (%v)", got, err)
+	}
+}
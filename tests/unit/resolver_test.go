@@ -1,6 +1,8 @@
 package unit
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
@@ -558,3 +560,358 @@ func TestPrefixWithAllTransformationStrategies(t *testing.T) {
 		})
 	}
 }
+
+// Test per-position segment transforms configured via segment_transforms
+func TestPathTransformSegmentTransforms(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms []string
+		path       []string
+		want       string
+	}{
+		{
+			name:       "mixed per-segment transform",
+			transforms: []string{"upper", "lower", "upper"},
+			path:       []string{"database", "HOST", "prod"},
+			want:       "DATABASE_host_PROD",
+		},
+		{
+			name:       "last transform applies to extra segments",
+			transforms: []string{"upper", "lower"},
+			path:       []string{"database", "Primary", "HOST"},
+			want:       "DATABASE_primary_host",
+		},
+		{
+			name:       "fewer segments than transforms",
+			transforms: []string{"preserve", "upper", "lower"},
+			path:       []string{"Database", "host"},
+			want:       "Database_HOST",
+		},
+		{
+			name:       "no transforms falls back to case_transform",
+			transforms: nil,
+			path:       []string{"database", "host"},
+			want:       "DATABASE_HOST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:         "_",
+				CaseTransform:     "upper",
+				PrefixMode:        "prepend",
+				SegmentTransforms: tt.transforms,
+			})
+			got, err := r.Transform(tt.path)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// Test that a degenerate separator cannot silently merge path segments
+func TestPathTransformMissingSeparator(t *testing.T) {
+	r := resolver.NewResolver("", "upper", "", "prepend")
+
+	_, err := r.Transform([]string{"database", "host"})
+	if !errors.Is(err, resolver.ErrMissingSeparator) {
+		t.Fatalf("Transform() error = %v, want ErrMissingSeparator", err)
+	}
+	if !strings.Contains(err.Error(), `"DATABASEHOST"`) {
+		t.Errorf("expected error to name the resolved variable, got %v", err)
+	}
+
+	// name_char_map deleting the separator is caught in the final name
+	deleting := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:     "_",
+		CaseTransform: "upper",
+		NameCharMap:   map[string]string{"_": ""},
+	})
+	if _, err := deleting.Transform([]string{"database", "host"}); !errors.Is(err, resolver.ErrMissingSeparator) {
+		t.Errorf("Transform() with the separator mapped away error = %v, want ErrMissingSeparator", err)
+	}
+
+	// Mapping the separator to another character keeps segments distinguishable
+	mapping := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:     ".",
+		CaseTransform: "upper",
+		NameCharMap:   map[string]string{".": "_"},
+	})
+	if got, err := mapping.Transform([]string{"database", "host"}); err != nil || got != "DATABASE_HOST" {
+		t.Errorf("Transform() with the separator mapped = %q, %v, want %q", got, err, "DATABASE_HOST")
+	}
+
+	// A single segment needs no separator
+	got, err := r.Transform([]string{"database"})
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if got != "DATABASE" {
+		t.Errorf("Transform() = %q, want %q", got, "DATABASE")
+	}
+}
+
+// Test the screaming transform replaces dashes and dots with the separator
+func TestPathTransformScreaming(t *testing.T) {
+	tests := []struct {
+		name      string
+		separator string
+		chars     string
+		path      []string
+		want      string
+	}{
+		{"dashes with underscore", "_", "", []string{"service", "api-v2", "host"}, "SERVICE_API_V2_HOST"},
+		{"dots with underscore", "_", "", []string{"db.primary", "port"}, "DB_PRIMARY_PORT"},
+		{"custom separator", "-", "", []string{"api.v2", "url"}, "API-V2-URL"},
+		{"custom characters", "_", "/", []string{"api/v2", "api-key"}, "API_V2_API-KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:      tt.separator,
+				CaseTransform:  "screaming",
+				PrefixMode:     "prepend",
+				ScreamingChars: tt.chars,
+			})
+			got, err := r.Transform(tt.path)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// Screaming can be selected for a single position
+	r := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:         "_",
+		CaseTransform:     "upper",
+		PrefixMode:        "prepend",
+		SegmentTransforms: []string{"lower", "screaming"},
+	})
+	got, err := r.Transform([]string{"Service", "api-v2"})
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if got != "service_API_V2" {
+		t.Errorf("Transform() = %q, want %q", got, "service_API_V2")
+	}
+}
+
+// Test collapse_separators removes repeated separators from the resolved name
+func TestPathTransformCollapseSeparators(t *testing.T) {
+	tests := []struct {
+		name     string
+		collapse bool
+		prefix   string
+		path     []string
+		want     string
+	}{
+		{"trailing separator in segment", true, "", []string{"db_", "host"}, "db_host"},
+		{"leading and trailing separators", true, "", []string{"db__", "__host"}, "db_host"},
+		{"prefix seam", true, "MyApp__", []string{"_db", "host"}, "MyApp_db_host"},
+		{"disabled keeps runs", false, "", []string{"db_", "host"}, "db__host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:          "_",
+				CaseTransform:      "preserve",
+				Prefix:             tt.prefix,
+				PrefixMode:         "prepend",
+				CollapseSeparators: tt.collapse,
+			})
+			got, err := r.Transform(tt.path)
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// Test camel_split inserting the separator at camelCase boundaries
+func TestResolverCamelSplit(t *testing.T) {
+	tests := []struct {
+		name          string
+		segment       string
+		caseTransform string
+		camelSplit    bool
+		want          string
+	}{
+		{"camel case upper", "apiKey", "upper", true, "API_KEY"},
+		{"leading acronym", "HTTPServer", "upper", true, "HTTP_SERVER"},
+		{"inner acronym", "myAPIEndpoint", "upper", true, "MY_API_ENDPOINT"},
+		{"digit boundary", "v2Api", "upper", true, "V2_API"},
+		{"already separated", "api_key", "upper", true, "API_KEY"},
+		{"preserve keeps case", "apiKey", "preserve", true, "api_Key"},
+		{"disabled", "apiKey", "upper", false, "APIKEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:     "_",
+				CaseTransform: tt.caseTransform,
+				CamelSplit:    tt.camelSplit,
+			})
+			got, err := r.Transform([]string{"app", tt.segment})
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if want := resolver.TransformSegment("app", tt.caseTransform, "_") + "_" + tt.want; got != want {
+				t.Errorf("Transform() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// Test name_char_map replacing characters in the resolved name
+func TestResolverNameCharMap(t *testing.T) {
+	r := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:     "_",
+		CaseTransform: "upper",
+		NameCharMap:   map[string]string{".": "_"},
+	})
+
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"config.api", "endpoint"}, "CONFIG_API_ENDPOINT"},
+		{[]string{"database", "host"}, "DATABASE_HOST"},
+	}
+	for _, tt := range tests {
+		got, err := r.Transform(tt.path)
+		if err != nil {
+			t.Fatalf("Transform(%v) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if got := r.MapNameChars("config.api.endpoint"); got != "config_api_endpoint" {
+		t.Errorf("MapNameChars() = %q, want %q", got, "config_api_endpoint")
+	}
+	if got := resolver.NewResolver("_", "upper", "", "prepend").MapNameChars("a.b"); got != "a.b" {
+		t.Errorf("MapNameChars() without a map = %q, want unchanged", got)
+	}
+}
+
+// Test join_separator reading paths separately from the name separator
+func TestResolverJoinSeparator(t *testing.T) {
+	r := resolver.NewResolverWithOptions(resolver.Options{
+		Separator:     "_",
+		CaseTransform: "upper",
+		JoinSeparator: "/",
+	})
+
+	tests := []struct {
+		name    string
+		path    []string
+		want    string
+		wantErr error
+	}{
+		{"segments", []string{"path", "to"}, "PATH_TO", nil},
+		{"joined segment", []string{"path/to/value"}, "PATH_TO_VALUE", nil},
+		{"mixed", []string{"path/to", "value"}, "PATH_TO_VALUE", nil},
+		{"empty joined segment", []string{"path//to"}, "", resolver.ErrEmptySegment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Transform(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transform(%v) error = %v, want %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// Without a join separator, "/" is kept in the name
+	plain := resolver.NewResolver("_", "upper", "", "prepend")
+	if got, _ := plain.Transform([]string{"path/to", "value"}); got != "PATH/TO_VALUE" {
+		t.Errorf("Transform() without join separator = %q, want %q", got, "PATH/TO_VALUE")
+	}
+}
+
+// Test drop_empty_segments filtering empty segments instead of erroring
+func TestResolverDropEmptySegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		drop    bool
+		path    []string
+		want    string
+		wantErr error
+	}{
+		{"dropped empty segment", true, []string{"app", "", "host"}, "APP_HOST", nil},
+		{"dropped whitespace segment", true, []string{"app", "  ", "host"}, "APP_HOST", nil},
+		{"single remaining segment", true, []string{"", "host"}, "HOST", nil},
+		{"all empty still errors", true, []string{"", " "}, "", resolver.ErrEmptySegment},
+		{"disabled rejects empty segment", false, []string{"app", "", "host"}, "", resolver.ErrEmptySegment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:         "_",
+				CaseTransform:     "upper",
+				DropEmptySegments: tt.drop,
+			})
+			got, err := r.Transform(tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Transform(%v) error = %v, want %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+// Test prefix_separators choosing the separator by leading path segment
+func TestResolverPrefixSeparators(t *testing.T) {
+	separators := map[string]string{"app1": "_", "APP2": "-"}
+
+	tests := []struct {
+		name       string
+		prefixMode string
+		path       []string
+		want       string
+	}{
+		{"underscore prefix", "filter_only", []string{"app1", "database", "host"}, "APP1_DATABASE_HOST"},
+		{"dash prefix", "filter_only", []string{"app2", "database", "host"}, "APP2-DATABASE-HOST"},
+		{"unlisted prefix uses default", "filter_only", []string{"other", "host"}, "OTHER.HOST"},
+		{"ignored in prepend mode", "prepend", []string{"app2", "host"}, "APP2.HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.NewResolverWithOptions(resolver.Options{
+				Separator:        ".",
+				CaseTransform:    "upper",
+				PrefixMode:       tt.prefixMode,
+				PrefixSeparators: separators,
+			})
+			got, err := r.Transform(tt.path)
+			if err != nil {
+				t.Fatalf("Transform(%v) error = %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Transform(%v) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+// Test that secret_variables stay fetchable by exact path while their names are masked in logs
+func TestSecretVariablesMaskedInLogs(t *testing.T) {
+	t.Setenv("SECRET_TEST_API_TOKEN", "token-value")
+	t.Setenv("SECRET_TEST_PLAIN", "plain-value")
+
+	var logs bytes.Buffer
+	prov := provider.New(logger.NewWithOutput(logger.DEBUG, &logs))
+	cfg, err := structpb.NewStruct(map[string]interface{}{
+		"secret_variables":  []interface{}{"SECRET_TEST_API_TOKEN", "SECRET_TEST_MISSING"},
+		"log_fetch_success": true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "secret-test", Config: cfg}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	resp, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{"SECRET_TEST_API_TOKEN"}})
+	if err != nil {
+		t.Fatalf("fetch of secret failed: %v", err)
+	}
+	if got := resp.Value.Fields["value"].GetStringValue(); got != "token-value" {
+		t.Errorf("expected secret value, got %q", got)
+	}
+	if _, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{"secret", "test", "missing"}}); err == nil {
+		t.Fatal("expected fetch of missing secret to fail")
+	}
+	if _, err := prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{"SECRET_TEST_PLAIN"}}); err != nil {
+		t.Fatalf("fetch of plain variable failed: %v", err)
+	}
+
+	output := logs.String()
+	for _, name := range []string{"SECRET_TEST_API_TOKEN", "SECRET_TEST_MISSING", "missing]"} {
+		if strings.Contains(output, name) {
+			t.Errorf("secret name %q leaked into logs:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "successfully fetched [secret]") {
+		t.Errorf("expected masked success log, got:\n%s", output)
+	}
+	if !strings.Contains(output, "successfully fetched SECRET_TEST_PLAIN") {
+		t.Errorf("expected plain variable name in logs, got:\n%s", output)
+	}
+}
+
+// Test that Init validation failures mask secret_variables names in logs
+func TestSecretVariablesMaskedInInitLogs(t *testing.T) {
+	t.Setenv("SECRET_INIT_SHORT", "abc")
+	t.Setenv("SECRET_INIT_MODE", "strict")
+
+	secrets := []interface{}{"SECRET_INIT_MISSING", "SECRET_INIT_SHORT", "SECRET_INIT_MODE", "SECRET_INIT_DECLARED"}
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+	}{
+		{"required missing", map[string]interface{}{
+			"required_variables": []interface{}{"SECRET_INIT_MISSING"},
+		}},
+		{"length constraint", map[string]interface{}{
+			"required_variables":   []interface{}{"SECRET_INIT_SHORT"},
+			"variable_constraints": map[string]interface{}{"SECRET_INIT_SHORT": map[string]interface{}{"min_length": 8}},
+		}},
+		{"conditional requirement", map[string]interface{}{
+			"conditional_requirements": []interface{}{
+				map[string]interface{}{"when_variable": "SECRET_INIT_MODE", "when_equals": "strict", "require": "SECRET_INIT_MISSING"},
+			},
+		}},
+		{"declared path", map[string]interface{}{
+			"declared_paths":          []interface{}{[]interface{}{"secret", "init", "declared"}},
+			"declared_paths_required": true,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			prov := provider.New(logger.NewWithOutput(logger.DEBUG, &logs))
+			tt.config["secret_variables"] = secrets
+			cfg, err := structpb.NewStruct(tt.config)
+			if err != nil {
+				t.Fatalf("failed to create config: %v", err)
+			}
+			if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "secret-test", Config: cfg}); err == nil {
+				t.Fatal("expected init to fail")
+			}
+
+			output := logs.String()
+			if strings.Contains(output, "SECRET_INIT") || strings.Contains(output, "strict") {
+				t.Errorf("secret name or condition leaked into logs:\n%s", output)
+			}
+			if !strings.Contains(output, "[secret]") {
+				t.Errorf("expected masked name in logs, got:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/resolver"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	vars := map[string]string{
+		"USER":     "alice",
+		"GREETING": "Hello {{.USER}}",
+		"CYCLE_A":  "{{.CYCLE_B}}",
+		"CYCLE_B":  "{{.CYCLE_A}}",
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		strict    bool
+		want      string
+		wantError error
+	}{
+		{"no template", "plain value", false, "plain value", nil},
+		{"simple template", "Hello {{.USER}}", false, "Hello alice", nil},
+		{"nested template", "{{.GREETING}}!", false, "Hello alice!", nil},
+		{"root variable in block", "{{with .GREETING}}{{$.USER}}{{end}}", false, "alice", nil},
+		{"missing key renders empty", "Hello {{.MISSING_USER}}", false, "Hello ", nil},
+		{"missing key strict", "Hello {{.MISSING_USER}}", true, "", resolver.ErrMissingTemplateKey},
+		{"invalid template", "Hello {{.USER", false, "", resolver.ErrInvalidTemplate},
+		{"template cycle", "{{.CYCLE_A}}", false, "", resolver.ErrExpansionTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.RenderTemplate(tt.input, mapLookup(vars), tt.strict, resolver.DefaultMaxExpansionDepth)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
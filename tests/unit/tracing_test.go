@@ -0,0 +1,120 @@
+//go:build otel
+// +build otel
+
+package unit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/logger"
+	"github.com/autonomous-bits/nomos-provider-environment-variables/internal/provider"
+	pb "github.com/autonomous-bits/nomos/libs/provider-proto/gen/go/nomos/provider/v1"
+)
+
+func TestTracingInterceptor(t *testing.T) {
+	t.Setenv("TRACING_TEST_VAR", "value")
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	interceptor := provider.TracingInterceptor(tp.Tracer("test"))
+
+	prov := provider.New(logger.NewWithOutput(logger.ERROR, &bytes.Buffer{}))
+	if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "tracing-test"}); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	fetch := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return prov.Fetch(ctx, req.(*pb.FetchRequest))
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/nomos.provider.v1.ProviderService/Fetch"}
+
+	if _, err := interceptor(context.Background(), &pb.FetchRequest{Path: []string{"TRACING_TEST_VAR"}}, info, fetch); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if _, err := interceptor(context.Background(), &pb.FetchRequest{Path: []string{"TRACING_TEST_MISSING"}}, info, fetch); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	wantStatus := []codes.Code{codes.OK, codes.NotFound}
+	for i, span := range spans {
+		if span.Name != "nomos.provider.v1.ProviderService/Fetch" {
+			t.Errorf("span %d: unexpected name %q", i, span.Name)
+		}
+		if span.SpanKind != trace.SpanKindServer {
+			t.Errorf("span %d: expected server span, got %v", i, span.SpanKind)
+		}
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+		if got := attrs["rpc.method"].AsString(); got != "Fetch" {
+			t.Errorf("span %d: expected rpc.method Fetch, got %q", i, got)
+		}
+		if got := attrs["rpc.service"].AsString(); got != "nomos.provider.v1.ProviderService" {
+			t.Errorf("span %d: unexpected rpc.service %q", i, got)
+		}
+		if got := codes.Code(attrs["rpc.grpc.status_code"].AsInt64()); got != wantStatus[i] {
+			t.Errorf("span %d: expected status %s, got %s", i, wantStatus[i], got)
+		}
+	}
+	if spans[1].Status.Code != otelcodes.Error {
+		t.Errorf("expected failed fetch span to have error status, got %v", spans[1].Status.Code)
+	}
+	if strings.Contains(spans[1].Status.Description, "TRACING_TEST_MISSING") {
+		t.Errorf("span status leaked variable name: %q", spans[1].Status.Description)
+	}
+}
+
+func TestTracingInterceptorContinuesIncomingTrace(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	interceptor := provider.TracingInterceptor(tp.Tracer("test"))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-"+traceID+"-"+parentID+"-01"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/nomos.provider.v1.ProviderService/Health"}
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return "response", nil
+	}
+	if _, err := interceptor(ctx, "request", info, handler); err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("expected span in incoming trace %s, got %s", traceID, got)
+	}
+	if got := spans[0].Parent.SpanID().String(); got != parentID || !spans[0].Parent.IsRemote() {
+		t.Errorf("expected remote parent %s, got %s (remote %v)", parentID, got, spans[0].Parent.IsRemote())
+	}
+}
@@ -215,6 +215,7 @@ func TestTransformSegment(t *testing.T) {
 		name      string
 		segment   string
 		transform string
+		separator string // empty uses "_"
 		want      string
 	}{
 		// Uppercase transformations
@@ -262,13 +263,37 @@ func TestTransformSegment(t *testing.T) {
 			transform: "preserve",
 			want:      "DataBase",
 		},
+		// Screaming transformations
+		{
+			name:      "screaming dash",
+			segment:   "api-v2",
+			transform: "screaming",
+			want:      "API_V2",
+		},
+		{
+			name:      "screaming dot",
+			segment:   "db.primary",
+			transform: "screaming",
+			want:      "DB_PRIMARY",
+		},
+		{
+			name:      "screaming custom separator",
+			segment:   "api-v2",
+			transform: "screaming",
+			separator: "__",
+			want:      "API__V2",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := resolver.TransformSegment(tt.segment, tt.transform)
+			separator := tt.separator
+			if separator == "" {
+				separator = "_"
+			}
+			got := resolver.TransformSegment(tt.segment, tt.transform, separator)
 			if got != tt.want {
-				t.Errorf("TransformSegment(%q, %q) = %q, want %q", tt.segment, tt.transform, got, tt.want)
+				t.Errorf("TransformSegment(%q, %q, %q) = %q, want %q", tt.segment, tt.transform, separator, got, tt.want)
 			}
 		})
 	}
@@ -330,7 +355,7 @@ func TestTransformSegments(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := resolver.TransformSegments(tt.segments, tt.transform)
+			got := resolver.TransformSegments(tt.segments, tt.transform, "_")
 
 			if len(got) != len(tt.want) {
 				t.Errorf("TransformSegments() length = %d, want %d", len(got), len(tt.want))
@@ -419,6 +419,135 @@ func TestRequiredVariablesWithPrefix(t *testing.T) {
 	}
 }
 
+// Unit test for conditional_requirements rules evaluated during Init
+func TestConditionalRequirements(t *testing.T) {
+	timestamp := time.Now().UnixNano()
+	storageVar := fmt.Sprintf("COND_STORAGE_%d", timestamp)
+	bucketVar := fmt.Sprintf("COND_S3_BUCKET_%d", timestamp)
+
+	rules := []interface{}{
+		map[string]interface{}{"when_variable": storageVar, "when_equals": "s3", "require": bucketVar},
+	}
+
+	tests := []struct {
+		name              string
+		storage           string // empty leaves the condition variable unset
+		bucket            string // empty leaves the required variable unset
+		wantErrorContains string
+	}{
+		{name: "condition met and required variable present", storage: "s3", bucket: "my-bucket"},
+		{name: "condition met and required variable missing", storage: "s3", wantErrorContains: fmt.Sprintf("%s (required when %s=\"s3\")", bucketVar, storageVar)},
+		{name: "condition unmet by value", storage: "local"},
+		{name: "condition variable unset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.storage != "" {
+				t.Setenv(storageVar, tt.storage)
+			}
+			if tt.bucket != "" {
+				t.Setenv(bucketVar, tt.bucket)
+			}
+
+			prov := provider.New(logger.New(logger.ERROR))
+			configStruct, err := structpb.NewStruct(map[string]interface{}{"conditional_requirements": rules})
+			if err != nil {
+				t.Fatalf("failed to create config struct: %v", err)
+			}
+
+			_, err = prov.Init(context.Background(), &pb.InitRequest{Alias: "test-provider", Config: configStruct})
+
+			if tt.wantErrorContains == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("expected InvalidArgument, got: %v", err)
+			}
+			if msg := status.Convert(err).Message(); !strings.Contains(msg, tt.wantErrorContains) {
+				t.Errorf("expected error message to contain %q, got: %q", tt.wantErrorContains, msg)
+			}
+		})
+	}
+}
+
+func TestVariableConstraints(t *testing.T) {
+	varName := fmt.Sprintf("CONSTRAINED_API_KEY_%d", time.Now().UnixNano())
+	constraints := map[string]interface{}{
+		varName: map[string]interface{}{"min_length": 4, "max_length": 6},
+	}
+
+	tests := []struct {
+		name              string
+		value             string
+		wantErrorContains string
+	}{
+		{name: "under min_length", value: "abc", wantErrorContains: varName + " is shorter than min_length 4"},
+		{name: "at min_length", value: "abcd"},
+		{name: "at max_length", value: "abcdef"},
+		{name: "over max_length", value: "abcdefg", wantErrorContains: varName + " is longer than max_length 6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(varName, tt.value)
+
+			check := func(t *testing.T, err error) {
+				t.Helper()
+				if tt.wantErrorContains == "" {
+					if err != nil {
+						t.Fatalf("expected no error, got: %v", err)
+					}
+					return
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected InvalidArgument, got: %v", err)
+				}
+				msg := status.Convert(err).Message()
+				if !strings.Contains(msg, tt.wantErrorContains) {
+					t.Errorf("expected error message to contain %q, got: %q", tt.wantErrorContains, msg)
+				}
+				if strings.Contains(msg, tt.value) {
+					t.Errorf("error message leaked the value: %q", msg)
+				}
+			}
+
+			t.Run("init for required variable", func(t *testing.T) {
+				prov := provider.New(logger.New(logger.ERROR))
+				configStruct, err := structpb.NewStruct(map[string]interface{}{
+					"required_variables":   []interface{}{varName},
+					"variable_constraints": constraints,
+				})
+				if err != nil {
+					t.Fatalf("failed to create config struct: %v", err)
+				}
+				_, err = prov.Init(context.Background(), &pb.InitRequest{Alias: "test-provider", Config: configStruct})
+				check(t, err)
+			})
+
+			t.Run("fetch", func(t *testing.T) {
+				prov := provider.New(logger.New(logger.ERROR))
+				configStruct, err := structpb.NewStruct(map[string]interface{}{
+					"variable_constraints":         constraints,
+					"enforce_constraints_on_fetch": true,
+				})
+				if err != nil {
+					t.Fatalf("failed to create config struct: %v", err)
+				}
+				if _, err := prov.Init(context.Background(), &pb.InitRequest{Alias: "test-provider", Config: configStruct}); err != nil {
+					t.Fatalf("init failed: %v", err)
+				}
+				_, err = prov.Fetch(context.Background(), &pb.FetchRequest{Path: []string{varName}})
+				check(t, err)
+			})
+		})
+	}
+}
+
 // Helper function to convert []string to []interface{} for protobuf
 func convertToInterfaceSlice(strs []string) []interface{} {
 	result := make([]interface{}, len(strs))